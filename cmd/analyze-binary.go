@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// binaryFormat is the platform a binary has been built for, named like GOOS and GOARCH.
+type binaryFormat struct {
+	OS   string
+	Arch string
+	Bits int
+}
+
+var errUnknownBinaryFormat = errors.New("neither an ELF, PE nor Mach-O binary")
+
+// inspectBinaryFormat reads the platform from the ELF, PE or Mach-O header of the binary without executing it.
+func inspectBinaryFormat(fileName string) (binaryFormat, error) {
+	if elfFile, err := elf.Open(fileName); err == nil {
+		defer elfFile.Close()
+		return elfBinaryFormat(elfFile), nil
+	}
+	if peFile, err := pe.Open(fileName); err == nil {
+		defer peFile.Close()
+		return peBinaryFormat(peFile), nil
+	}
+	if machoFile, err := macho.Open(fileName); err == nil {
+		defer machoFile.Close()
+		return machoBinaryFormat(machoFile.Cpu), nil
+	}
+	if fatFile, err := macho.OpenFat(fileName); err == nil {
+		defer fatFile.Close()
+		format := binaryFormat{OS: "darwin", Bits: 64}
+		var archs []string
+		for _, arch := range fatFile.Arches {
+			archs = append(archs, machoBinaryFormat(arch.Cpu).Arch)
+		}
+		format.Arch = strings.Join(archs, "+")
+		return format, nil
+	}
+	if _, err := os.Stat(fileName); err != nil {
+		return binaryFormat{}, err
+	}
+	return binaryFormat{}, errUnknownBinaryFormat
+}
+
+func elfBinaryFormat(file *elf.File) binaryFormat {
+	format := binaryFormat{OS: "linux", Bits: 32}
+	if file.Class == elf.ELFCLASS64 {
+		format.Bits = 64
+	}
+	switch file.OSABI {
+	case elf.ELFOSABI_FREEBSD:
+		format.OS = "freebsd"
+	case elf.ELFOSABI_SOLARIS:
+		format.OS = "solaris"
+	case elf.ELFOSABI_AIX:
+		format.OS = "aix"
+	}
+	switch file.Machine {
+	case elf.EM_X86_64:
+		format.Arch = "amd64"
+	case elf.EM_386:
+		format.Arch = "386"
+	case elf.EM_AARCH64:
+		format.Arch = "arm64"
+	case elf.EM_ARM:
+		format.Arch = "arm"
+	case elf.EM_PPC64:
+		format.Arch = "ppc64"
+		if file.ByteOrder.String() == "LittleEndian" {
+			format.Arch = "ppc64le"
+		}
+	case elf.EM_S390:
+		format.Arch = "s390x"
+	case elf.EM_RISCV:
+		format.Arch = "riscv64"
+	case elf.EM_SPARCV9:
+		format.Arch = "sparcv9"
+	default:
+		format.Arch = strings.ToLower(strings.TrimPrefix(file.Machine.String(), "EM_"))
+	}
+	return format
+}
+
+func peBinaryFormat(file *pe.File) binaryFormat {
+	format := binaryFormat{OS: "windows", Bits: 32}
+	if _, is64Bit := file.OptionalHeader.(*pe.OptionalHeader64); is64Bit {
+		format.Bits = 64
+	}
+	switch file.Machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		format.Arch = "amd64"
+	case pe.IMAGE_FILE_MACHINE_I386:
+		format.Arch = "386"
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		format.Arch = "arm64"
+	case pe.IMAGE_FILE_MACHINE_ARMNT:
+		format.Arch = "arm"
+	default:
+		format.Arch = "unknown"
+	}
+	return format
+}
+
+func machoBinaryFormat(cpu macho.Cpu) binaryFormat {
+	format := binaryFormat{OS: "darwin", Bits: 64}
+	switch cpu {
+	case macho.CpuAmd64:
+		format.Arch = "amd64"
+	case macho.CpuArm64:
+		format.Arch = "arm64"
+	case macho.Cpu386:
+		format.Arch, format.Bits = "386", 32
+	case macho.CpuPpc:
+		format.Arch, format.Bits = "ppc", 32
+	case macho.CpuPpc64:
+		format.Arch = "ppc64"
+	default:
+		format.Arch = strings.ToLower(cpu.String())
+	}
+	return format
+}
+
+// findJvmLibrary returns the JVM shared library of the installation the java binary belongs to.
+func findJvmLibrary(javaBinary string) string {
+	resolved, err := filepath.EvalSymlinks(javaBinary)
+	if err != nil {
+		resolved = javaBinary
+	}
+	home := filepath.Dir(filepath.Dir(resolved))
+	candidates := []string{
+		filepath.Join(home, "lib", "server", "libjvm.so"),
+		filepath.Join(home, "lib", "server", "libjvm.dylib"),
+		filepath.Join(home, "bin", "server", "jvm.dll"),
+		filepath.Join(home, "bin", "client", "jvm.dll"),
+	}
+	// java 8 and older place the library below an architecture specific directory
+	legacyCandidates, _ := filepath.Glob(filepath.Join(home, "lib", "*", "server", "libjvm.so"))
+	candidates = append(candidates, legacyCandidates...)
+	for _, candidate := range candidates {
+		if stat, err := os.Stat(candidate); err == nil && stat.Mode().IsRegular() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// inspectJavaBinaryFormat records the platform of the java binary, or of the JVM library if the java binary is
+// a launcher script. It returns false, if the operating system or architecture is known to differ from the current one.
+func inspectJavaBinaryFormat(info *JavaInfo) bool {
+	format, err := inspectBinaryFormat(info.Exe)
+	if err != nil {
+		if jvmLibrary := findJvmLibrary(info.Exe); jvmLibrary != "" {
+			format, err = inspectBinaryFormat(jvmLibrary)
+		}
+	}
+	if err != nil {
+		log.Debugf("Cannot determine the platform of java binary %s: %s", info.Exe, err)
+		return true
+	}
+	info.BinaryOS, info.BinaryArch, info.BinaryBits = format.OS, format.Arch, format.Bits
+	return isRunnableBinaryFormat(format, runtime.GOOS, runtime.GOARCH)
+}
+
+// compatibleArchitectures are the architectures, that a platform executes besides its own:
+// 32-bit x86 binaries on 64-bit windows and linux, x86-64 binaries on apple silicon via Rosetta 2.
+var compatibleArchitectures = map[string]string{
+	"windows/amd64": "386",
+	"linux/amd64":   "386",
+	"darwin/arm64":  "amd64",
+}
+
+// isRunnableBinaryFormat checks whether a binary of the format can be executed on the platform.
+// Universal binaries contain several architectures.
+func isRunnableBinaryFormat(format binaryFormat, goos string, goarch string) bool {
+	if format.OS != goos {
+		return false
+	}
+	compatibleArch := compatibleArchitectures[goos+"/"+goarch]
+	for _, arch := range strings.Split(format.Arch, "+") {
+		if arch == goarch || arch == compatibleArch {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeJavaInstallationStatically reads the release file of the installation instead of executing the java binary.
+func analyzeJavaInstallationStatically(info *JavaInfo) {
+	releaseFile := releaseFileOf(info.Exe)
+	content, err := os.ReadFile(releaseFile)
+	if os.IsNotExist(err) && filepath.Base(filepath.Dir(releaseFile)) == "jre" {
+		content, err = os.ReadFile(filepath.Join(filepath.Dir(filepath.Dir(releaseFile)), "release"))
+	}
+	if err != nil {
+		addErrorText(info, err, "")
+		return
+	}
+	applyReleaseProperties(info, parseReleaseFile(string(content)))
+	info.AnalysisMethod = ReleaseFile
+}
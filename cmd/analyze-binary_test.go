@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func Test_inspectBinaryFormat(t *testing.T) {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Skipf("test binary not found: %v", err)
+	}
+	format, err := inspectBinaryFormat(executable)
+	if err != nil {
+		t.Fatalf("inspectBinaryFormat() error = %v", err)
+	}
+	// ELF binaries of other platforms are reported as linux unless their OS ABI is set
+	isLinuxWindowsOrDarwin := runtime.GOOS == "linux" || runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+	if format.OS != runtime.GOOS && isLinuxWindowsOrDarwin {
+		t.Errorf("inspectBinaryFormat() OS = %v, want %v", format.OS, runtime.GOOS)
+	}
+	if format.Arch != runtime.GOARCH {
+		t.Errorf("inspectBinaryFormat() Arch = %v, want %v", format.Arch, runtime.GOARCH)
+	}
+}
+
+func Test_inspectBinaryFormatOfScript(t *testing.T) {
+	script := t.TempDir() + "/java"
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inspectBinaryFormat(script); err != errUnknownBinaryFormat {
+		t.Errorf("inspectBinaryFormat() error = %v, want %v", err, errUnknownBinaryFormat)
+	}
+}
+
+// headerFixture writes the header structures to a file, a binary without sections and load commands.
+func headerFixture(t *testing.T, name string, order binary.ByteOrder, headers ...interface{}) string {
+	var buffer bytes.Buffer
+	for _, header := range headers {
+		if err := binary.Write(&buffer, order, header); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fileName := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(fileName, buffer.Bytes(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return fileName
+}
+
+func elfFixture(t *testing.T, machine elf.Machine, osABI elf.OSABI) string {
+	header := elf.Header64{Type: uint16(elf.ET_EXEC), Machine: uint16(machine), Version: uint32(elf.EV_CURRENT), Ehsize: 64}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	header.Ident[elf.EI_OSABI] = byte(osABI)
+	return headerFixture(t, "java", binary.LittleEndian, header)
+}
+
+func peFixture(t *testing.T, machine uint16, optionalHeader interface{}) string {
+	// the DOS header only has to point to the PE signature
+	dosHeader := make([]byte, 64)
+	copy(dosHeader, "MZ")
+	binary.LittleEndian.PutUint32(dosHeader[0x3c:], 64)
+	fileHeader := pe.FileHeader{Machine: machine, SizeOfOptionalHeader: uint16(binary.Size(optionalHeader))}
+	return headerFixture(t, "java.exe", binary.LittleEndian, dosHeader, []byte("PE\x00\x00"), fileHeader, optionalHeader)
+}
+
+func machoFixture(t *testing.T, cpu macho.Cpu) string {
+	header := macho.FileHeader{Magic: macho.Magic64, Cpu: cpu, Type: macho.TypeExec}
+	return headerFixture(t, "java", binary.LittleEndian, header, uint32(0))
+}
+
+func Test_inspectBinaryFormatOfHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		want     binaryFormat
+	}{
+		{"elf x64", elfFixture(t, elf.EM_X86_64, elf.ELFOSABI_NONE), binaryFormat{"linux", "amd64", 64}},
+		{"elf aarch64", elfFixture(t, elf.EM_AARCH64, elf.ELFOSABI_NONE), binaryFormat{"linux", "arm64", 64}},
+		{"elf ppc64le", elfFixture(t, elf.EM_PPC64, elf.ELFOSABI_NONE), binaryFormat{"linux", "ppc64le", 64}},
+		{"elf solaris sparc", elfFixture(t, elf.EM_SPARCV9, elf.ELFOSABI_SOLARIS), binaryFormat{"solaris", "sparcv9", 64}},
+		{"pe x64", peFixture(t, pe.IMAGE_FILE_MACHINE_AMD64, pe.OptionalHeader64{Magic: 0x20b, NumberOfRvaAndSizes: 16}), binaryFormat{"windows", "amd64", 64}},
+		{"pe x86", peFixture(t, pe.IMAGE_FILE_MACHINE_I386, pe.OptionalHeader32{Magic: 0x10b, NumberOfRvaAndSizes: 16}), binaryFormat{"windows", "386", 32}},
+		{"pe arm64", peFixture(t, pe.IMAGE_FILE_MACHINE_ARM64, pe.OptionalHeader64{Magic: 0x20b, NumberOfRvaAndSizes: 16}), binaryFormat{"windows", "arm64", 64}},
+		{"mach-o x64", machoFixture(t, macho.CpuAmd64), binaryFormat{"darwin", "amd64", 64}},
+		{"mach-o arm64", machoFixture(t, macho.CpuArm64), binaryFormat{"darwin", "arm64", 64}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inspectBinaryFormat(tt.fileName)
+			if err != nil || got != tt.want {
+				t.Errorf("inspectBinaryFormat() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isRunnableBinaryFormat(t *testing.T) {
+	tests := []struct {
+		format binaryFormat
+		goos   string
+		goarch string
+		want   bool
+	}{
+		{binaryFormat{"linux", "amd64", 64}, "linux", "amd64", true},
+		{binaryFormat{"linux", "arm64", 64}, "linux", "amd64", false},
+		{binaryFormat{"linux", "amd64", 64}, "linux", "arm64", false},
+		{binaryFormat{"windows", "amd64", 64}, "linux", "amd64", false},
+		{binaryFormat{"windows", "386", 32}, "windows", "amd64", true},
+		{binaryFormat{"windows", "amd64", 64}, "windows", "386", false},
+		{binaryFormat{"darwin", "amd64+arm64", 64}, "darwin", "arm64", true},
+		{binaryFormat{"linux", "386", 32}, "linux", "amd64", true},
+		{binaryFormat{"linux", "386", 32}, "linux", "arm64", false},
+		{binaryFormat{"darwin", "amd64", 64}, "darwin", "arm64", true},
+		{binaryFormat{"darwin", "arm64", 64}, "darwin", "amd64", false},
+	}
+	for _, tt := range tests {
+		if got := isRunnableBinaryFormat(tt.format, tt.goos, tt.goarch); got != tt.want {
+			t.Errorf("isRunnableBinaryFormat(%+v, %s, %s) = %v, want %v", tt.format, tt.goos, tt.goarch, got, tt.want)
+		}
+	}
+}
+
+func Test_inspectJavaBinaryFormatOfOtherArchitecture(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("requires ELF binaries to be native")
+	}
+	machine, arch := elf.EM_S390, "s390x"
+	if runtime.GOARCH == "s390x" {
+		machine, arch = elf.EM_X86_64, "amd64"
+	}
+	info := JavaInfo{Exe: elfFixture(t, machine, elf.ELFOSABI_NONE)}
+	if inspectJavaBinaryFormat(&info) || info.BinaryOS != "linux" || info.BinaryArch != arch {
+		t.Errorf("inspectJavaBinaryFormat() = true, %s/%s, want false for a %s binary", info.BinaryOS, info.BinaryArch, arch)
+	}
+}
@@ -1,7 +1,8 @@
 package cmd
 
 import (
-	"os/exec"
+	"errors"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -9,33 +10,45 @@ import (
 
 func analyzeJavaBinaryMain(info *JavaInfo) {
 	info.Valid = true
+	if !inspectJavaBinaryFormat(info) {
+		log.Infof("Java binary %s is built for %s/%s, reading its release file instead of executing it", info.Exe, info.BinaryOS, info.BinaryArch)
+		analyzeJavaInstallationStatically(info)
+		return
+	}
+	if noExec {
+		log.Debugf("Reading the release file of java binary %s instead of executing it", info.Exe)
+		analyzeJavaInstallationStatically(info)
+		return
+	}
 	err := _analyzeJavaBinary(info, false)
-	if err != nil {
+	// a binary, that hung, is not retried
+	if err != nil && !errors.Is(err, errExecTimeout) && isSudoRetryAllowed() {
 		err = _analyzeJavaBinary(info, true)
-		//note that errorText is already added to info.ErrorText
-		if err != nil {
-			log.Warnf("Failed to analyze java binary %s: %s", info.Exe, err.Error())
-		}
-
+	}
+	//note that errorText is already added to info.ErrorText
+	if err != nil {
+		log.Warnf("Failed to analyze java binary %s: %s", info.Exe, err.Error())
 	}
 }
 
 func _analyzeJavaBinary(info *JavaInfo, sudo bool) error {
-	cmdArgs := [4]string{"-n", info.Exe, "-XshowSettings:properties", "-version"}
+	cmdArgs := []string{"-XshowSettings:properties", "-version"}
 	var out []byte
 	var err error
 	if sudo {
-		command := exec.Command("sudo", cmdArgs[0:3]...)
-		out, err = command.CombinedOutput()
+		out, err = runJavaBinary("sudo", sudoArguments(info.Exe, cmdArgs...)...)
 	} else {
-		command := exec.Command(info.Exe, cmdArgs[2:4]...)
-		out, err = command.CombinedOutput()
+		out, err = runJavaBinary(info.Exe, cmdArgs...)
 	}
 	if err == nil {
 		if len(out) > 0 {
 			l := strings.Split(string(out), "\n")
 			extractProperties(l, info)
 		}
+		info.AnalysisMethod = Executed
+		if sudo {
+			info.AnalysisMethod = ExecutedWithSudo
+		}
 		return nil
 	}
 
@@ -63,41 +76,58 @@ func extractPropertiesFromVersionOutput(info *JavaInfo) error {
 
 	var err error
 	err = nil
-	out, err := exec.Command(info.Exe, "-version").CombinedOutput()
+	out, err := runJavaBinary(info.Exe, "-version")
 	if err != nil {
 		log.Warnf("extractPropertiesFromVersionOutput exe:%s, error:%s", info.Exe, err)
 		addErrorText(info, err, string(out))
 		return err
 	}
+	info.AnalysisMethod = Executed
 	versionOutput := strings.Split(string(out), "\n")
 	info.MajorVersion, info.BuildNumber = extractMajorAndBuildNumber(extractVersionString(versionOutput[0]))
-	info.RuntimeName = extractRuntimeName(versionOutput[1])
+	if len(versionOutput) > 1 {
+		info.RuntimeName = extractRuntimeName(versionOutput[1])
+	}
+	if len(versionOutput) > 2 {
+		// e.g. "Java HotSpot(TM) 64-Bit Server VM (build 20.45-b01, mixed mode)"
+		info.VmName, _, _ = strings.Cut(strings.TrimSpace(versionOutput[2]), " (")
+	}
 	return nil
 }
 
+// extractMajorAndBuildNumber parses the legacy "1.8.0_202" as well as the "17.0.10" and "21" version scheme.
 func extractMajorAndBuildNumber(versionString string) (int, int) {
-	major, _ := regexp.Compile(`(\d+)\.(\d).(\d).*?_?(\d+)?`)
-	versionFormat := major.MatchString(versionString)
-	if versionFormat {
-		allString := major.FindStringSubmatch(versionString)
-		v1, _ := strconv.Atoi(allString[1])
-		v2, _ := strconv.Atoi(allString[2])
-		v3, _ := strconv.Atoi(allString[3])
-		v4, _ := strconv.Atoi(allString[4])
-		build := extractBuildNumber(v3, v4)
-		if v1 == 1 {
-			return v2, build
-		}
-		return v1, build
+	major := regexp.MustCompile(`^(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:_(\d+))?`)
+	allString := major.FindStringSubmatch(strings.TrimSpace(versionString))
+	if allString == nil {
+		return 0, 0
 	}
-	return 0, 0
-
+	v1, _ := strconv.Atoi(allString[1])
+	v2, _ := strconv.Atoi(allString[2])
+	v3, _ := strconv.Atoi(allString[3])
+	v4, _ := strconv.Atoi(allString[4])
+	build := extractBuildNumber(v3, v4)
+	if v1 == 1 {
+		return v2, build
+	}
+	return v1, build
 }
 
+// extractRuntimeName removes the build and the vendor specific version from the runtime line of 'java -version',
+// e.g. "OpenJDK Runtime Environment Temurin-17.0.9+9 (build 17.0.9+9)".
 func extractRuntimeName(runtimeLine string) string {
-	pattern, _ := regexp.Compile(`(\D+).*\(.*?\)`)
-	runtimeName := pattern.FindStringSubmatch(runtimeLine)[1]
-	return strings.Trim(runtimeName, " ")
+	runtimeName, _, _ := strings.Cut(runtimeLine, " (")
+	if end := strings.Index(runtimeName, "Runtime Environment"); end >= 0 {
+		return runtimeName[:end+len("Runtime Environment")]
+	}
+	var words []string
+	for _, word := range strings.Fields(runtimeName) {
+		if strings.ContainsAny(word, "0123456789") {
+			break
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
 }
 
 func extractVersionString(versionLine string) string {
@@ -113,21 +143,96 @@ func extractVersionString(versionLine string) string {
 }
 
 func extractProperties(outputLine []string, info *JavaInfo) {
-	var validProperty = regexp.MustCompile("^(?P<Key>[a-z.]+) = (?P<Value>.+)$")
+	info.Properties = parseSystemProperties(outputLine)
+	for key, value := range info.Properties {
+		switch key {
+		case "java.vendor":
+			info.Vendor = value
+		case "java.version":
+			info.MajorVersion, info.BuildNumber = extractMajorAndBuildNumber(value)
+		case "java.runtime.name":
+			info.RuntimeName = value
+		case "java.vm.name":
+			info.VmName = value
+		case "java.vm.vendor":
+			info.VmVendor = value
+		case "java.vm.version":
+			info.VmVersion = value
+		case "java.vendor.version":
+			info.VendorVersion = value
+		case "java.specification.version":
+			info.SpecificationVersion = value
+		case "os.arch":
+			info.OsArch = value
+		}
+	}
+}
+
+// parseSystemProperties parses the "key = value" lines printed by -XshowSettings:properties.
+// Values with several entries, like java.library.path, continue on the following lines, which are indented deeper.
+// Their entries are joined with the path list separator.
+func parseSystemProperties(outputLine []string) map[string]string {
+	var validProperty = regexp.MustCompile(`^(\s+)([^\s=]+) =(?: (.*))?$`)
+	properties := map[string]string{}
+	currentKey := ""
+	currentIndent := 0
 	for _, l1 := range outputLine {
-		line := strings.TrimSpace(l1)
-		if validProperty.MatchString(line) {
-			submatch := validProperty.FindStringSubmatch(line)
-			key := submatch[1]
-			value := submatch[2]
-			switch key {
-			case "java.vendor":
-				info.Vendor = value
-			case "java.version":
-				info.MajorVersion, info.BuildNumber = extractMajorAndBuildNumber(value)
-			case "java.runtime.name":
-				info.RuntimeName = value
+		line := strings.TrimRight(l1, "\r")
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if currentKey != "" && indent > currentIndent && strings.TrimSpace(line) != "" {
+			if properties[currentKey] != "" {
+				properties[currentKey] += string(os.PathListSeparator)
 			}
+			properties[currentKey] += strings.TrimSpace(line)
+			continue
+		}
+		currentKey = ""
+		if submatch := validProperty.FindStringSubmatch(line); submatch != nil {
+			currentKey = submatch[2]
+			currentIndent = len(submatch[1])
+			properties[currentKey] = strings.TrimSpace(submatch[3])
+		}
+	}
+	return properties
+}
+
+// parseReleaseFile parses the KEY="value" lines of the 'release' file in the home directory of a java installation.
+func parseReleaseFile(content string) map[string]string {
+	properties := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found || strings.HasPrefix(key, "#") {
+			continue
+		}
+		properties[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), "\"")
+	}
+	return properties
+}
+
+// applyReleaseProperties sets the information, that can be derived statically from the 'release' file.
+func applyReleaseProperties(info *JavaInfo, properties map[string]string) {
+	if javaVersion, found := properties["JAVA_VERSION"]; found {
+		info.MajorVersion, info.BuildNumber = extractMajorAndBuildNumber(javaVersion)
+	}
+	info.Vendor = properties["IMPLEMENTOR"]
+	info.VendorVersion = properties["IMPLEMENTOR_VERSION"]
+	info.OsArch = properties["OS_ARCH"]
+	switch strings.ToLower(properties["JVM_VARIANT"]) {
+	case "hotspot":
+		info.JvmImplementation = JvmHotSpot
+	case "openj9":
+		info.JvmImplementation = JvmOpenJ9
+	}
+	if properties["BUILD_TYPE"] == "commercial" {
+		// Oracle JDK builds are the only commercial builds, older ones do not name the implementor
+		if info.Vendor == "" {
+			info.Vendor = "Oracle Corporation"
 		}
+		info.RuntimeName = "Java(TM) SE Runtime Environment"
+	} else if info.Vendor != "" {
+		info.RuntimeName = "OpenJDK Runtime Environment"
+	}
+	if info.MajorVersion == 0 {
+		addErrorText(info, errors.New("no java version found in release file"), "")
 	}
 }
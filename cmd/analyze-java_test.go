@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -16,6 +19,9 @@ func Test_extractMajorVersion(t *testing.T) {
 		{"8_202-release", "1.8.0_202-release", 8, 202},
 		{"11", "11.0.2", 11, 2},
 		{"17", "17.0.5", 17, 5},
+		{"17.0.10", "17.0.10", 17, 10},
+		{"21 GA", "21", 21, 0},
+		{"11 release file", "11.0.21+9", 11, 21},
 		{"6", "1.6.0_45-b06", 6, 45},
 		{"5", "1.5.0_22-b03", 5, 22},
 	}
@@ -62,7 +68,9 @@ func Test_extractRuntimeName(t *testing.T) {
 		// TODO: Add test cases.
 		{"Java SE", args{runtimeLine: "Java(TM) SE Runtime Environment (build 1.6.0_45-b06)"}, "Java(TM) SE Runtime Environment"},
 		{"Oracle OpenJDK", args{runtimeLine: "OpenJDK Runtime Environment 18.9 (build 11.0.2+9)"}, "OpenJDK Runtime Environment"},
-		{"JetBrains s.r.o.", args{runtimeLine: "OpenJDK Runtime Environment JBR-17.0.5+1-653.14-jcef (build 17.0.5+1-b653.14)"}, "OpenJDK Runtime Environment JBR-"},
+		{"JetBrains s.r.o.", args{runtimeLine: "OpenJDK Runtime Environment JBR-17.0.5+1-653.14-jcef (build 17.0.5+1-b653.14)"}, "OpenJDK Runtime Environment"},
+		{"Temurin", args{runtimeLine: "OpenJDK Runtime Environment Temurin-17.0.9+9 (build 17.0.9+9)"}, "OpenJDK Runtime Environment"},
+		{"Semeru", args{runtimeLine: "IBM Semeru Runtime Open Edition 17.0.9.0 (build 17.0.9+9)"}, "IBM Semeru Runtime Open Edition"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -72,3 +80,25 @@ func Test_extractRuntimeName(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseSystemProperties(t *testing.T) {
+	output := "Property settings:\n" +
+		"    java.home = /usr/lib/jvm/temurin-17\n" +
+		"    java.library.path = /usr/java/packages/lib\n" +
+		"        /usr/lib64\n" +
+		"        /lib64\n" +
+		"    sun.arch.data.model = 64\r\n" +
+		"    user.variant = \n" +
+		"\n" +
+		"openjdk version \"17.0.9\" 2023-10-17\n"
+	separator := string(os.PathListSeparator)
+	want := map[string]string{
+		"java.home":           "/usr/lib/jvm/temurin-17",
+		"java.library.path":   "/usr/java/packages/lib" + separator + "/usr/lib64" + separator + "/lib64",
+		"sun.arch.data.model": "64",
+		"user.variant":        "",
+	}
+	if got := parseSystemProperties(strings.Split(output, "\n")); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSystemProperties() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// javaLaunch is what a JVM has been started with, parsed from its command line.
+type javaLaunch struct {
+	MainClass        string
+	Jar              string
+	ClassPath        []string
+	SystemProperties map[string]string
+	WorkingDir       string
+	JavaHome         string
+}
+
+// jvmOptionsWithValue are the options of the java launcher, that take the next argument as their value.
+var jvmOptionsWithValue = map[string]bool{
+	"-cp": true, "-classpath": true, "--class-path": true, "-p": true, "--module-path": true,
+	"--upgrade-module-path": true, "--add-modules": true, "--limit-modules": true, "--add-reads": true,
+	"--add-exports": true, "--add-opens": true, "--patch-module": true,
+}
+
+// parseJavaCommandLine separates the JVM options from the main class or jar and its arguments.
+func parseJavaCommandLine(commandLine []string) javaLaunch {
+	launch := javaLaunch{SystemProperties: map[string]string{}}
+	for i := 1; i < len(commandLine); i++ {
+		argument := commandLine[i]
+		switch {
+		case argument == "-jar" && i+1 < len(commandLine):
+			launch.Jar = commandLine[i+1]
+			return launch
+		case (argument == "-m" || argument == "--module") && i+1 < len(commandLine):
+			_, launch.MainClass, _ = strings.Cut(commandLine[i+1], "/")
+			return launch
+		case argument == "-cp" || argument == "-classpath" || argument == "--class-path":
+			if i+1 < len(commandLine) {
+				launch.ClassPath = filepath.SplitList(commandLine[i+1])
+			}
+			i++
+		case jvmOptionsWithValue[argument]:
+			i++
+		case strings.HasPrefix(argument, "-D"):
+			key, value, _ := strings.Cut(strings.TrimPrefix(argument, "-D"), "=")
+			launch.SystemProperties[key] = value
+		case strings.HasPrefix(argument, "-"):
+			// any other JVM option
+		default:
+			launch.MainClass = argument
+			return launch
+		}
+	}
+	return launch
+}
+
+// resolve makes a path of the command line absolute, relative paths are relative to the working directory of the JVM.
+func (launch javaLaunch) resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) || launch.WorkingDir == "" {
+		return path
+	}
+	return filepath.Join(launch.WorkingDir, path)
+}
+
+// homeDirectory returns the installation directory given by the system property, an empty string if it is not set
+// or cannot be resolved. Files of the installation would be looked up relative to the directory of the scan otherwise.
+func (launch javaLaunch) homeDirectory(property string) string {
+	home := launch.resolve(launch.SystemProperties[property])
+	if !filepath.IsAbs(home) {
+		return ""
+	}
+	return home
+}
+
+// findClassPathJar returns the first jar of the class path, whose name matches the pattern.
+func (launch javaLaunch) findClassPathJar(pattern *regexp.Regexp) string {
+	for _, entry := range launch.ClassPath {
+		if pattern.MatchString(filepath.Base(entry)) {
+			return launch.resolve(entry)
+		}
+	}
+	return ""
+}
+
+// applicationRule recognizes an application by the main class or the name of the jar it has been started with.
+type applicationRule struct {
+	Name        string
+	MainClasses []string
+	Jar         *regexp.Regexp
+	Version     func(launch javaLaunch, manifest map[string]string) string
+}
+
+var jarVersionPattern = regexp.MustCompile(`-(\d[\w.]*)\.jar$`)
+var productVersionPattern = regexp.MustCompile(`\d+\.\d+[\w.]*`)
+
+var applicationRules = []applicationRule{
+	{Name: "Tomcat", MainClasses: []string{"org.apache.catalina.startup.Bootstrap"},
+		Version: func(launch javaLaunch, manifest map[string]string) string {
+			home := launch.homeDirectory("catalina.home")
+			if home == "" {
+				// without catalina.home, Bootstrap uses the parent directory of bin/bootstrap.jar
+				if bootstrapJar := launch.findClassPathJar(regexp.MustCompile(`^bootstrap\.jar$`)); filepath.IsAbs(bootstrapJar) {
+					home = filepath.Dir(filepath.Dir(bootstrapJar))
+				}
+			}
+			if home == "" {
+				return ""
+			}
+			return readJarManifest(filepath.Join(home, "lib", "catalina.jar"))["Implementation-Version"]
+		}},
+	{Name: "JBoss/WildFly", MainClasses: []string{"org.jboss.modules.Main"},
+		Version: func(launch javaLaunch, manifest map[string]string) string {
+			// e.g. "WildFly Full 26.1.3.Final (WildFly Core 18.1.2.Final)"
+			home := launch.homeDirectory("jboss.home.dir")
+			if home == "" {
+				return ""
+			}
+			content, _ := os.ReadFile(filepath.Join(home, "version.txt"))
+			return productVersionPattern.FindString(string(content))
+		}},
+	{Name: "WebLogic", MainClasses: []string{"weblogic.Server", "weblogic.NodeManager"},
+		Version: func(launch javaLaunch, manifest map[string]string) string {
+			return readJarManifest(launch.findClassPathJar(regexp.MustCompile(`^weblogic\.jar$`)))["Implementation-Version"]
+		}},
+	{Name: "WebSphere Liberty", MainClasses: []string{"com.ibm.ws.kernel.boot.cmdline.EnvCheck"}, Jar: regexp.MustCompile(`^ws-server\.jar$`),
+		Version: func(launch javaLaunch, manifest map[string]string) string {
+			// the jar is located in wlp/bin/tools
+			installDir := filepath.Dir(filepath.Dir(filepath.Dir(launch.resolve(launch.Jar))))
+			content, _ := os.ReadFile(filepath.Join(installDir, "lib", "versions", "WebSphereApplicationServer.properties"))
+			return parseReleaseFile(string(content))["com.ibm.websphere.productVersion"]
+		}},
+	{Name: "Jetty", MainClasses: []string{"org.eclipse.jetty.start.Main"}, Jar: regexp.MustCompile(`^start\.jar$`),
+		Version: func(launch javaLaunch, manifest map[string]string) string {
+			return manifest["Implementation-Version"]
+		}},
+	{Name: "Kafka", MainClasses: []string{"kafka.Kafka"},
+		Version: func(launch javaLaunch, manifest map[string]string) string {
+			return versionOfJarName(launch.findClassPathJar(regexp.MustCompile(`^kafka_[\d.]+-.*\.jar$`)))
+		}},
+	{Name: "Elasticsearch", MainClasses: []string{"org.elasticsearch.bootstrap.Elasticsearch"},
+		Version: func(launch javaLaunch, manifest map[string]string) string {
+			home := launch.homeDirectory("es.path.home")
+			if home == "" {
+				return ""
+			}
+			jars, _ := filepath.Glob(filepath.Join(home, "lib", "elasticsearch-[0-9]*.jar"))
+			if len(jars) == 0 {
+				return ""
+			}
+			return versionOfJarName(jars[0])
+		}},
+	{Name: "Jenkins", Jar: regexp.MustCompile(`^jenkins\.war$`),
+		Version: func(launch javaLaunch, manifest map[string]string) string {
+			return manifest["Jenkins-Version"]
+		}},
+	{Name: "IntelliJ IDEA", MainClasses: []string{"com.intellij.idea.Main"},
+		Version: func(launch javaLaunch, manifest map[string]string) string {
+			// the bundled runtime is located in the jbr directory of the installation, e.g. "IU-233.11799.241"
+			content, _ := os.ReadFile(filepath.Join(filepath.Dir(launch.JavaHome), "build.txt"))
+			return strings.TrimSpace(string(content))
+		}},
+}
+
+// detectApplication recognizes the application server, middleware or tool running on a JVM.
+func detectApplication(commandLine []string, workingDir string, javaBinary string) (name string, version string) {
+	launch := parseJavaCommandLine(commandLine)
+	launch.WorkingDir = workingDir
+	launch.JavaHome = filepath.Dir(filepath.Dir(javaBinary))
+	var manifest map[string]string
+	if launch.Jar != "" {
+		manifest = readJarManifest(launch.resolve(launch.Jar))
+		if launch.MainClass == "" {
+			launch.MainClass = manifest["Main-Class"]
+		}
+	}
+	for _, rule := range applicationRules {
+		isMatch := containsFold(rule.MainClasses, launch.MainClass)
+		if rule.Jar != nil && launch.Jar != "" && rule.Jar.MatchString(filepath.Base(launch.Jar)) {
+			isMatch = true
+		}
+		if isMatch {
+			return rule.Name, rule.Version(launch, manifest)
+		}
+	}
+	if springBootVersion, isSpringBoot := manifest["Spring-Boot-Version"]; isSpringBoot {
+		return "Spring Boot", springBootVersion
+	}
+	return "", ""
+}
+
+// readJarManifest returns the main attributes of the manifest of a jar, an empty map if it cannot be read.
+func readJarManifest(jarFile string) map[string]string {
+	if jarFile == "" {
+		return map[string]string{}
+	}
+	jar, err := zip.OpenReader(jarFile)
+	if err != nil {
+		return map[string]string{}
+	}
+	defer jar.Close()
+	for _, entry := range jar.File {
+		if !strings.EqualFold(entry.Name, "META-INF/MANIFEST.MF") {
+			continue
+		}
+		file, err := entry.Open()
+		if err != nil {
+			return map[string]string{}
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(io.LimitReader(file, maxReleaseFileSize))
+		return parseManifest(string(content))
+	}
+	return map[string]string{}
+}
+
+// parseManifest parses the "Name: value" attributes of the main section of a manifest.
+// Lines starting with a space continue the previous value.
+func parseManifest(content string) map[string]string {
+	attributes := map[string]string{}
+	lastName := ""
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if line == "" && len(attributes) > 0 {
+			// the sections of the individual entries follow
+			break
+		}
+		if strings.HasPrefix(line, " ") && lastName != "" {
+			attributes[lastName] += strings.TrimPrefix(line, " ")
+			continue
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			lastName = ""
+			continue
+		}
+		lastName = strings.TrimSpace(name)
+		attributes[lastName] = strings.TrimSpace(value)
+	}
+	return attributes
+}
+
+func versionOfJarName(jarFile string) string {
+	if match := jarVersionPattern.FindStringSubmatch(filepath.Base(jarFile)); match != nil {
+		return match[1]
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeJarWithManifest(t *testing.T, jarFile string, manifest string) {
+	if err := os.MkdirAll(filepath.Dir(jarFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(jarFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	jar := zip.NewWriter(file)
+	entry, err := jar.Create("META-INF/MANIFEST.MF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = entry.Write([]byte(manifest)); err != nil {
+		t.Fatal(err)
+	}
+	if err = jar.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_detectApplication(t *testing.T) {
+	root := t.TempDir()
+	writeJarWithManifest(t, filepath.Join(root, "tomcat", "lib", "catalina.jar"), "Manifest-Version: 1.0\r\nImplementation-Title: Apache Tomcat\r\nImplementation-Version: 9.0.83\r\n\r\nName: org/apache/catalina/\r\nImplementation-Version: 0\r\n")
+	writeJarWithManifest(t, filepath.Join(root, "app", "shop.jar"), "Manifest-Version: 1.0\nMain-Class: org.springframework.boot.loader.JarLauncher\nStart-Class: com.example.shop.ShopApplicati\n on\nSpring-Boot-Version: 3.2.0\n")
+
+	tests := []struct {
+		name        string
+		commandLine []string
+		workingDir  string
+		wantName    string
+		wantVersion string
+	}{
+		{"tomcat", []string{"java", "-Xmx512m", "-Dcatalina.home=" + filepath.Join(root, "tomcat"), "-classpath", "bin/bootstrap.jar", "org.apache.catalina.startup.Bootstrap", "start"}, "", "Tomcat", "9.0.83"},
+		{"tomcat home of bootstrap jar", []string{"java", "-classpath", "bin/bootstrap.jar", "org.apache.catalina.startup.Bootstrap", "start"}, filepath.Join(root, "tomcat"), "Tomcat", "9.0.83"},
+		{"tomcat without home", []string{"java", "-classpath", "bin/bootstrap.jar", "org.apache.catalina.startup.Bootstrap", "start"}, "", "Tomcat", ""},
+		{"jboss without home", []string{"java", "-cp", "jboss-modules.jar", "org.jboss.modules.Main"}, "", "JBoss/WildFly", ""},
+		{"spring boot relative to working dir", []string{"java", "-jar", "shop.jar", "--server.port=8081"}, filepath.Join(root, "app"), "Spring Boot", "3.2.0"},
+		{"kafka", []string{"java", "-cp", "/opt/kafka/libs/kafka-clients-3.6.0.jar" + string(os.PathListSeparator) + "/opt/kafka/libs/kafka_2.13-3.6.0.jar", "kafka.Kafka", "server.properties"}, "", "Kafka", "3.6.0"},
+		{"main class after module option", []string{"java", "--add-opens", "java.base/java.lang=ALL-UNNAMED", "weblogic.Server"}, "", "WebLogic", ""},
+		{"unknown", []string{"java", "-cp", "weblogic.Server", "com.example.Main"}, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, version := detectApplication(tt.commandLine, tt.workingDir, "/usr/lib/jvm/java-17/bin/java")
+			if name != tt.wantName || version != tt.wantVersion {
+				t.Errorf("detectApplication() = (%v, %v), want (%v, %v)", name, version, tt.wantName, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func Test_parseManifest(t *testing.T) {
+	manifest := parseManifest("Manifest-Version: 1.0\r\nStart-Class: com.example.shop.ShopApplicati\r\n on\r\n\r\nName: x\r\nStart-Class: other\r\n")
+	if got := manifest["Start-Class"]; got != "com.example.shop.ShopApplication" {
+		t.Errorf("parseManifest() Start-Class = %v, want the continued value of the main section", got)
+	}
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var noCache bool
+var cacheFile string
+var cachePruneAll bool
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "manage the cache of analyzed java binaries used by the file system scan",
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "remove cache entries of java binaries, that have been changed or removed",
+	Run: func(cmd *cobra.Command, args []string) {
+		PruneCache()
+	},
+}
+
+func init() {
+	cacheCmd.PersistentFlags().StringVar(&cacheFile, "cache-file", defaultCacheFile(), "file the analysis results of java binaries are cached in")
+	cachePruneCmd.Flags().BoolVar(&cachePruneAll, "all", false, "remove all cache entries")
+
+	cacheCmd.AddCommand(cachePruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func defaultCacheFile() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "java-scanner-cache.json"
+	}
+	return filepath.Join(cacheDir, "java-scanner", "analysis-cache.json")
+}
+
+// fileState is the state of a file, that changes when the file is replaced or modified.
+type fileState struct {
+	Exists  bool
+	Device  uint64
+	Inode   uint64
+	Size    int64
+	ModTime int64
+}
+
+func readFileState(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	identity, _ := getFileIdentity(info)
+	return fileState{Exists: true, Device: identity.Device, Inode: identity.Inode, Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+}
+
+type analysisCacheEntry struct {
+	Binary  fileState
+	Release fileState
+	Info    JavaInfo
+}
+
+// analysisCache holds the analysis results of java binaries keyed by their path.
+// An entry is reused as long as the java binary and the release file of its installation are unchanged.
+type analysisCache struct {
+	fileName string
+	entries  map[string]analysisCacheEntry
+}
+
+func loadAnalysisCache(fileName string) *analysisCache {
+	cache := &analysisCache{fileName: fileName, entries: map[string]analysisCacheEntry{}}
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf("Cannot read cache file %s: %s", fileName, err)
+		}
+		return cache
+	}
+	if err = json.Unmarshal(content, &cache.entries); err != nil {
+		log.Warnf("Ignoring invalid cache file %s: %s", fileName, err)
+		cache.entries = map[string]analysisCacheEntry{}
+	}
+	return cache
+}
+
+func (cache *analysisCache) save() error {
+	content, err := json.Marshal(cache.entries)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(cache.fileName), 0700); err != nil {
+		return err
+	}
+	temporaryFile := cache.fileName + ".tmp"
+	if err = os.WriteFile(temporaryFile, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(temporaryFile, cache.fileName)
+}
+
+func releaseFileOf(javaBinary string) string {
+	return filepath.Join(filepath.Dir(filepath.Dir(javaBinary)), "release")
+}
+
+// isCurrent checks whether the entry still describes the java binary.
+func (entry analysisCacheEntry) isCurrent(javaBinary string) bool {
+	return entry.Binary.Exists && entry.Binary == readFileState(javaBinary) && entry.Release == readFileState(releaseFileOf(javaBinary))
+}
+
+// analyzeJavaBinaryCached reuses the cached analysis result of unchanged java binaries
+// and analyzes all others via analyzeJavaBinaryMain.
+func analyzeJavaBinaryCached(cache *analysisCache, info *JavaInfo) {
+	if entry, found := cache.entries[info.Exe]; found && entry.isCurrent(info.Exe) {
+		log.Debugf("Using cached analysis of java binary %s", info.Exe)
+		applyCachedAnalysis(info, entry.Info)
+		return
+	}
+
+	binaryState := readFileState(info.Exe)
+	releaseState := readFileState(releaseFileOf(info.Exe))
+	analyzeJavaBinaryMain(info)
+	// failed analyses are not cached, they might succeed next time
+	if info.Valid {
+		cache.entries[info.Exe] = analysisCacheEntry{Binary: binaryState, Release: releaseState, Info: *info}
+	} else {
+		delete(cache.entries, info.Exe)
+	}
+}
+
+// applyCachedAnalysis copies the analysis result, but keeps the information about the current scan
+// and marks the result as taken from the cache.
+func applyCachedAnalysis(info *JavaInfo, cached JavaInfo) {
+	current := *info
+	*info = cached
+	info.AnalysisMethod = Cached
+	info.DetectionMethod = current.DetectionMethod
+	info.ScanTimestamp = current.ScanTimestamp
+	info.Hostname = current.Hostname
+	info.Username = current.Username
+	info.Exe = current.Exe
+}
+
+func PruneCache() {
+	cache := loadAnalysisCache(cacheFile)
+	countBefore := len(cache.entries)
+	for javaBinary, entry := range cache.entries {
+		if cachePruneAll || !entry.isCurrent(javaBinary) {
+			delete(cache.entries, javaBinary)
+		}
+	}
+	if err := cache.save(); err != nil {
+		log.Fatalf("failed writing cache file %s: %s", cacheFile, err)
+	}
+	log.Infof("Removed %d of %d entries from cache file %s", countBefore-len(cache.entries), countBefore, cacheFile)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// cacheFixture creates a java installation with a release file, that is read instead of executing the java binary.
+func cacheFixture(t *testing.T) string {
+	home := filepath.Join(t.TempDir(), "jdk-17")
+	if err := os.MkdirAll(filepath.Join(home, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "bin", "java"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "release"), []byte(temurinReleaseFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(home, "bin", "java")
+}
+
+func analyzeWithCache(cache *analysisCache, javaBinary string) JavaInfo {
+	info := JavaInfo{Exe: javaBinary, DetectionMethod: FileSystem}
+	analyzeJavaBinaryCached(cache, &info)
+	return info
+}
+
+func Test_analyzeJavaBinaryCached(t *testing.T) {
+	defer func(value bool) { noExec = value }(noExec)
+	noExec = true
+
+	tests := []struct {
+		name   string
+		change func(t *testing.T, javaBinary string)
+		want   AnalysisMethod
+	}{
+		{"unchanged", func(t *testing.T, javaBinary string) {}, Cached},
+		{"binary size changed", func(t *testing.T, javaBinary string) {
+			if err := os.WriteFile(javaBinary, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}, ReleaseFile},
+		{"binary modification time changed", func(t *testing.T, javaBinary string) {
+			modTime := time.Now().Add(time.Hour)
+			if err := os.Chtimes(javaBinary, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+		}, ReleaseFile},
+		{"release file changed", func(t *testing.T, javaBinary string) {
+			if err := os.WriteFile(releaseFileOf(javaBinary), []byte("JAVA_VERSION=\"17.0.10\"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}, ReleaseFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			javaBinary := cacheFixture(t)
+			cache := loadAnalysisCache(filepath.Join(t.TempDir(), "cache.json"))
+			if miss := analyzeWithCache(cache, javaBinary); miss.AnalysisMethod != ReleaseFile || miss.MajorVersion != 17 {
+				t.Fatalf("first analysis = %v, major version %d, want release-file, 17", miss.AnalysisMethod, miss.MajorVersion)
+			}
+			if err := cache.save(); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.change(t, javaBinary)
+			cache = loadAnalysisCache(cache.fileName)
+			second := analyzeWithCache(cache, javaBinary)
+			if second.AnalysisMethod != tt.want || second.MajorVersion != 17 || second.Exe != javaBinary || second.DetectionMethod != FileSystem {
+				t.Errorf("second analysis = %v of %s by %v, major version %d, want %v", second.AnalysisMethod, second.Exe,
+					second.DetectionMethod, second.MajorVersion, tt.want)
+			}
+		})
+	}
+}
+
+func Test_PruneCache(t *testing.T) {
+	defer func(value bool) { noExec = value }(noExec)
+	defer func(value string) { cacheFile = value }(cacheFile)
+	defer func(value bool) { cachePruneAll = value }(cachePruneAll)
+	noExec = true
+	cacheFile = filepath.Join(t.TempDir(), "cache.json")
+
+	removed, kept := cacheFixture(t), cacheFixture(t)
+	cache := loadAnalysisCache(cacheFile)
+	analyzeWithCache(cache, removed)
+	analyzeWithCache(cache, kept)
+	if err := cache.save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(removed); err != nil {
+		t.Fatal(err)
+	}
+
+	cachePruneAll = false
+	PruneCache()
+	entries := loadAnalysisCache(cacheFile).entries
+	if _, found := entries[removed]; found || len(entries) != 1 {
+		t.Errorf("PruneCache() kept %v, want only %s", sortedKeys(entries), kept)
+	}
+
+	cachePruneAll = true
+	PruneCache()
+	if entries := loadAnalysisCache(cacheFile).entries; len(entries) != 0 {
+		t.Errorf("PruneCache() with --all kept %v", sortedKeys(entries))
+	}
+}
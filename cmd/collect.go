@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var collectListenAddress string
+var collectStoreFile string
+
+var collectCmd = &cobra.Command{
+	Use:   "collect",
+	Short: "run a collector server, that receives findings pushed by 'scan --push-to'",
+	Long: `Runs a small HTTP server, that receives findings pushed by scanners via 'scan --push-to <url>'.
+
+The latest findings of every host are kept in a local store file and can be queried via
+  GET /findings?host=<hostname>&vendor=<vendor>&version=<major version>&license=<license category>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		Collect()
+	},
+}
+
+func init() {
+	collectCmd.Flags().StringVarP(&collectListenAddress, "listen", "l", ":8080", "address the collector listens on")
+	collectCmd.Flags().StringVarP(&collectStoreFile, "store-file", "s", "collector-store.jsonl", "file, where received findings are stored")
+
+	rootCmd.AddCommand(collectCmd)
+}
+
+// FindingsBatch is the set of findings a scanner pushes to the collector after a scan.
+type FindingsBatch struct {
+	Hostname string
+	Received time.Time
+	Findings []JavaInfo
+}
+
+// findingsStore keeps the latest batch of every host in memory and appends every received batch to a file.
+// On startup the file is replayed, so later batches of a host replace earlier ones.
+// The file is compacted to the latest batch of every host, when it holds more outdated batches than current ones.
+type findingsStore struct {
+	mutex    sync.RWMutex
+	fileName string
+	batches  map[string]FindingsBatch
+	lines    int
+}
+
+// pushClient is the client scanners push their findings with, a hung collector must not block the scan.
+var pushClient = &http.Client{Timeout: 60 * time.Second}
+
+func Collect() {
+	store, err := openFindingsStore(collectStoreFile)
+	if err != nil {
+		log.Fatalf("failed opening store file %s: %s", collectStoreFile, err)
+	}
+	log.Infof("Loaded findings of %d hosts from store file %s", len(store.batches), collectStoreFile)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/findings", store.handleFindings)
+	log.Infof("Collector listening on %s...", collectListenAddress)
+	log.Fatal(http.ListenAndServe(collectListenAddress, mux))
+}
+
+func collectorFindingsUrl(collectorUrl string) string {
+	return strings.TrimSuffix(collectorUrl, "/") + "/findings"
+}
+
+func openFindingsStore(fileName string) (*findingsStore, error) {
+	store := &findingsStore{fileName: fileName, batches: map[string]FindingsBatch{}}
+	file, err := os.Open(fileName)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var batch FindingsBatch
+		if err := json.Unmarshal(scanner.Bytes(), &batch); err != nil {
+			log.Warnf("Skipping invalid line in store file %s: %s", fileName, err)
+			continue
+		}
+		store.batches[batch.Hostname] = batch
+		store.lines++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if store.needsCompaction() {
+		if err := store.compact(); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+func (store *findingsStore) needsCompaction() bool {
+	return store.lines > 2*len(store.batches)
+}
+
+// compact rewrites the store file with the latest batch of every host. The new file replaces the old one atomically.
+func (store *findingsStore) compact() error {
+	compacted, err := os.CreateTemp(filepath.Dir(store.fileName), filepath.Base(store.fileName)+".*")
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(compacted)
+	for _, hostname := range sortedKeys(store.batches) {
+		line, err := json.Marshal(store.batches[hostname])
+		if err == nil {
+			_, err = writer.Write(append(line, '\n'))
+		}
+		if err != nil {
+			compacted.Close()
+			os.Remove(compacted.Name())
+			return err
+		}
+	}
+	err = writer.Flush()
+	if closeErr := compacted.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(compacted.Name(), store.fileName)
+	}
+	if err != nil {
+		os.Remove(compacted.Name())
+		return err
+	}
+	log.Infof("Compacted store file %s from %d to %d batches", store.fileName, store.lines, len(store.batches))
+	store.lines = len(store.batches)
+	return nil
+}
+
+func (store *findingsStore) add(batch FindingsBatch) error {
+	line, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	file, err := os.OpenFile(store.fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	store.batches[batch.Hostname] = batch
+	store.lines++
+	if store.needsCompaction() {
+		if err := store.compact(); err != nil {
+			log.Warnf("Failed to compact store file %s: %s", store.fileName, err)
+		}
+	}
+	return nil
+}
+
+func (store *findingsStore) query(host string, vendor string, version int, license string) []JavaInfo {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	result := []JavaInfo{}
+	for _, batch := range store.batches {
+		if host != "" && !strings.EqualFold(batch.Hostname, host) {
+			continue
+		}
+		for _, info := range batch.Findings {
+			if vendor != "" && !strings.Contains(strings.ToLower(info.Vendor), strings.ToLower(vendor)) {
+				continue
+			}
+			if version != 0 && info.MajorVersion != version {
+				continue
+			}
+			if license != "" && info.LicenseCategory.String() != license {
+				continue
+			}
+			result = append(result, info)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Hostname != result[j].Hostname {
+			return result[i].Hostname < result[j].Hostname
+		}
+		return result[i].Exe < result[j].Exe
+	})
+	return result
+}
+
+func (store *findingsStore) handleFindings(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+	case http.MethodPost:
+		var batch FindingsBatch
+		if err := json.NewDecoder(request.Body).Decode(&batch); err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if batch.Hostname == "" {
+			http.Error(writer, "missing hostname", http.StatusBadRequest)
+			return
+		}
+		batch.Received = time.Now()
+		if err := store.add(batch); err != nil {
+			log.Errorf("Failed to store findings of host %s: %s", batch.Hostname, err)
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		log.Infof("Received %d findings from host %s", len(batch.Findings), batch.Hostname)
+	case http.MethodGet:
+		parameters := request.URL.Query()
+		version := 0
+		if parameters.Get("version") != "" {
+			var err error
+			version, err = strconv.Atoi(parameters.Get("version"))
+			if err != nil {
+				http.Error(writer, "version must be a major version number", http.StatusBadRequest)
+				return
+			}
+		}
+		result := store.query(parameters.Get("host"), parameters.Get("vendor"), version, parameters.Get("license"))
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(result)
+	default:
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_pushFindingsToCollector(t *testing.T) {
+	store, err := openFindingsStore(filepath.Join(t.TempDir(), "store.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(store.handleFindings))
+	defer server.Close()
+
+	findings := []JavaInfo{
+		{Exe: "/usr/lib/jvm/temurin-17/bin/java", Vendor: "Eclipse Adoptium", MajorVersion: 17, LicenseCategory: LicenseOpenSource},
+		{Exe: "/opt/jdk1.8.0_391/bin/java", Vendor: "Oracle Corporation", MajorVersion: 8, LicenseCategory: LicenseOracleOTN},
+	}
+	if err := pushFindings(server.URL+"/", findings); err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := http.Get(server.URL + "/findings?vendor=oracle&license=oracle-otn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	var got []JavaInfo
+	if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Exe != "/opt/jdk1.8.0_391/bin/java" {
+		t.Errorf("query returned %+v, want the oracle finding", got)
+	}
+
+	// the store file is replayed by the next collector
+	reopened, err := openFindingsStore(store.fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostname, _ := os.Hostname()
+	if len(reopened.batches[hostname].Findings) != 2 {
+		t.Errorf("reopened store has batches %+v, want the 2 findings of %s", reopened.batches, hostname)
+	}
+}
+
+func Test_findingsStoreCompaction(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "store.jsonl")
+	store, err := openFindingsStore(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		for _, host := range []string{"host-a", "host-b"} {
+			if err := store.add(FindingsBatch{Hostname: host, Findings: []JavaInfo{{MajorVersion: 11 + i}}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(content), "\n"); lines > 2*len(store.batches) {
+		t.Errorf("store file has %d lines, want it compacted to at most %d", lines, 2*len(store.batches))
+	}
+	reopened, err := openFindingsStore(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"host-a", "host-b"} {
+		if got := reopened.batches[host].Findings[0].MajorVersion; got != 15 {
+			t.Errorf("latest batch of %s has version %d, want 15", host, got)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"strings"
+)
+
+// commercialFeature is the -XX flag of an Oracle JDK commercial feature.
+// Up to the java version, that made it free to use, its use requires a license,
+// even if the Oracle JDK may be used for free otherwise.
+type commercialFeature struct {
+	Flag      string
+	FreeSince int
+}
+
+// commercialFeatures are free to use since java 11, when Flight Recorder has been open sourced,
+// except for application class data sharing, which has been open sourced with java 10.
+var commercialFeatures = []commercialFeature{
+	{"UnlockCommercialFeatures", 11},
+	{"FlightRecorder", 11},
+	{"UseAppCDS", 10},
+	{"ResourceManagement", 11},
+}
+
+// javaOptionsWithOperand are the launcher options, whose value is the next argument.
+var javaOptionsWithOperand = map[string]bool{
+	"-cp": true, "-classpath": true, "--class-path": true, "-p": true, "--module-path": true, "--upgrade-module-path": true,
+	"--add-modules": true, "--limit-modules": true, "--add-reads": true, "--add-exports": true, "--add-opens": true,
+	"--patch-module": true, "--enable-native-access": true,
+}
+
+// parseCommercialFeatures returns the commercial features enabled by the command line of a JVM.
+// A flag given several times is enabled, if its last occurrence enables it. Only the JVM options are parsed,
+// which end with the main class or the operand of -jar or -m, the arguments of the application follow them.
+func parseCommercialFeatures(commandLine []string) []string {
+	enabled := map[string]bool{}
+	for i := 1; i < len(commandLine); i++ {
+		argument := commandLine[i]
+		if argument == "-jar" || argument == "-m" || argument == "--module" || !strings.HasPrefix(argument, "-") ||
+			strings.HasPrefix(argument, "--module=") {
+			break
+		}
+		switch {
+		case javaOptionsWithOperand[argument]:
+			i++
+		case strings.HasPrefix(argument, "-XX:+"):
+			enabled[strings.TrimPrefix(argument, "-XX:+")] = true
+		case strings.HasPrefix(argument, "-XX:-"):
+			enabled[strings.TrimPrefix(argument, "-XX:-")] = false
+		case strings.HasPrefix(argument, "-XX:StartFlightRecording"), strings.HasPrefix(argument, "-XX:FlightRecorderOptions"):
+			// starts a recording without -XX:+FlightRecorder
+			enabled["FlightRecorder"] = true
+		}
+	}
+	var features []string
+	for _, feature := range commercialFeatures {
+		if enabled[feature.Flag] {
+			features = append(features, feature.Flag)
+		}
+	}
+	return features
+}
+
+// isCommercialFeatureLicensed checks whether one of the commercial features enabled is subject to a license
+// in the java version of the Oracle JDK.
+func isCommercialFeatureLicensed(info *JavaInfo) bool {
+	if !isOracleJdk(info) || info.MajorVersion == 0 {
+		return false
+	}
+	for _, enabled := range info.CommercialFeatures {
+		for _, feature := range commercialFeatures {
+			if feature.Flag == enabled && info.MajorVersion < feature.FreeSince {
+				return true
+			}
+		}
+	}
+	return false
+}
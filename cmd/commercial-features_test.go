@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseCommercialFeatures(t *testing.T) {
+	tests := []struct {
+		name        string
+		commandLine []string
+		want        []string
+	}{
+		{"none", []string{"java", "-Xmx1g", "-jar", "app.jar"}, nil},
+		{"flight recorder", []string{"java", "-XX:+UnlockCommercialFeatures", "-XX:+FlightRecorder", "Main"}, []string{"UnlockCommercialFeatures", "FlightRecorder"}},
+		{"recording started", []string{"java", "-XX:+UnlockCommercialFeatures", "-XX:StartFlightRecording=duration=60s", "Main"}, []string{"UnlockCommercialFeatures", "FlightRecorder"}},
+		{"disabled again", []string{"java", "-XX:+UseAppCDS", "-XX:-UseAppCDS", "Main"}, nil},
+		{"resource management", []string{"java", "-XX:+ResourceManagement", "Main"}, []string{"ResourceManagement"}},
+		{"application arguments", []string{"java", "-Xmx1g", "Main", "-XX:+FlightRecorder"}, nil},
+		{"jar arguments", []string{"java", "-XX:+UseAppCDS", "-jar", "app.jar", "-XX:+FlightRecorder"}, []string{"UseAppCDS"}},
+		{"module arguments", []string{"java", "-m", "app/app.Main", "-XX:+UseAppCDS"}, nil},
+		{"class path operand", []string{"java", "-cp", "lib/*", "-XX:+FlightRecorder", "Main"}, []string{"FlightRecorder"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCommercialFeatures(tt.commandLine); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCommercialFeatures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_classifyLicenseWithCommercialFeatures(t *testing.T) {
+	oracleJdk := JavaInfo{Vendor: "Oracle Corporation", RuntimeName: "Java(TM) SE Runtime Environment", MajorVersion: 8, BuildNumber: 151,
+		CommercialFeatures: []string{"UnlockCommercialFeatures", "FlightRecorder"}}
+	if got := classifyLicense(&oracleJdk); got != LicenseOracleCommercialFeatures {
+		t.Errorf("classifyLicense() of Oracle JDK 8u151 = %v, want %v", got, LicenseOracleCommercialFeatures)
+	}
+	openJdk := oracleJdk
+	openJdk.RuntimeName = "OpenJDK Runtime Environment"
+	if got := classifyLicense(&openJdk); got != LicenseOpenSource {
+		t.Errorf("classifyLicense() of OpenJDK 8u151 = %v, want %v", got, LicenseOpenSource)
+	}
+}
+
+func Test_isCommercialFeatureLicensed(t *testing.T) {
+	tests := []struct {
+		name     string
+		major    int
+		features []string
+		want     bool
+	}{
+		{"flight recorder on 8", 8, []string{"FlightRecorder"}, true},
+		{"flight recorder on 10", 10, []string{"FlightRecorder"}, true},
+		{"flight recorder on 11", 11, []string{"FlightRecorder"}, false},
+		{"app cds on 9", 9, []string{"UseAppCDS"}, true},
+		{"app cds on 10", 10, []string{"UseAppCDS"}, false},
+		{"app cds and resource management on 10", 10, []string{"UseAppCDS", "ResourceManagement"}, true},
+		{"unknown version", 0, []string{"FlightRecorder"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := JavaInfo{Vendor: "Oracle Corporation", RuntimeName: "Java(TM) SE Runtime Environment", MajorVersion: tt.major, CommercialFeatures: tt.features}
+			if got := isCommercialFeatureLicensed(&info); got != tt.want {
+				t.Errorf("isCommercialFeatureLicensed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+var profileName string
+
+// policyRules is the policy configured inline via 'policy-rules' in the selected profile or the config file.
+var policyRules *Policy
+
+// builtinProfiles bundle the options commonly used for a kind of host.
+// Profiles of the same name in the config file are merged into these.
+var builtinProfiles = map[string]map[string]interface{}{
+	"server": {
+		"scan-running-processes":         true,
+		"scan-linux-alternatives":        true,
+		"scan-file-system":               true,
+		"scan-file-system-root-paths":    []string{"/usr/lib/jvm", "/usr/java", "/opt", "/usr/local"},
+		"scan-file-system-exclude-paths": []string{`/\.snapshots?/`},
+		"append-to-findings-json":        true,
+	},
+	"workstation": {
+		"scan-running-processes":      true,
+		"scan-current-path":           true,
+		"scan-windows-registry":       true,
+		"scan-file-system":            true,
+		"scan-file-system-root-paths": []string{"/usr/lib/jvm", "/opt", "/home"},
+	},
+	"container-host": {
+		"scan-running-processes":         true,
+		"scan-file-system":               true,
+		"scan-file-system-root-paths":    []string{"/usr/lib/jvm", "/var/lib/docker", "/var/lib/containerd"},
+		"scan-file-system-exclude-paths": []string{`^/var/lib/docker/overlay2/[^/]+/merged/`},
+		"append-to-findings-json":        true,
+	},
+}
+
+// applyConfiguration sets all flags of the command, that have not been given on the command line.
+// The value is taken from the first of the following sources, that sets it:
+// the environment variable JPS_<FLAG_NAME>, the selected profile and the config file.
+func applyConfiguration(cmd *cobra.Command) error {
+	if !cmd.Flags().Changed("profile") {
+		if value, found := os.LookupEnv(environmentVariableName("profile")); found {
+			profileName = value
+		} else {
+			profileName = viper.GetString("profile")
+		}
+	}
+	profile, err := loadProfile(profileName)
+	if err != nil {
+		return err
+	}
+
+	var flagErr error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed || flagErr != nil || flag.Name == "help" || flag.Name == "config" || flag.Name == "profile" {
+			return
+		}
+		value, found := os.LookupEnv(environmentVariableName(flag.Name))
+		if !found {
+			value, found = configuredFlagValue(profile, flag)
+		}
+		if !found {
+			value, found = configuredFlagValue(viper.GetViper(), flag)
+		}
+		if found {
+			if err := cmd.Flags().Set(flag.Name, value); err != nil {
+				flagErr = fmt.Errorf("invalid value '%s' for %s: %s", value, flag.Name, err)
+			}
+		}
+	})
+	if flagErr != nil {
+		return flagErr
+	}
+
+	for _, source := range []*viper.Viper{profile, viper.GetViper()} {
+		if source.IsSet("policy-rules") {
+			policyRules = &Policy{}
+			return source.UnmarshalKey("policy-rules", policyRules)
+		}
+	}
+	return nil
+}
+
+func loadProfile(name string) (*viper.Viper, error) {
+	profile := viper.New()
+	if name == "" {
+		return profile, nil
+	}
+	builtinProfile, isBuiltin := builtinProfiles[name]
+	if !isBuiltin && !viper.IsSet("profiles."+name) {
+		return nil, fmt.Errorf("unknown profile '%s', available profiles are %s", name, strings.Join(profileNames(), ", "))
+	}
+	settings := map[string]interface{}{}
+	for key, value := range builtinProfile {
+		settings[key] = value
+	}
+	if err := profile.MergeConfigMap(settings); err != nil {
+		return nil, err
+	}
+	if viper.IsSet("profiles." + name) {
+		if err := profile.MergeConfigMap(viper.GetStringMap("profiles." + name)); err != nil {
+			return nil, err
+		}
+	}
+	log.Infof("Using profile '%s'", name)
+	return profile, nil
+}
+
+func profileNames() []string {
+	names := []string{}
+	for name := range builtinProfiles {
+		names = append(names, name)
+	}
+	for name := range viper.GetStringMap("profiles") {
+		if _, isBuiltin := builtinProfiles[name]; !isBuiltin {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func configuredFlagValue(source *viper.Viper, flag *pflag.Flag) (string, bool) {
+	if !source.IsSet(flag.Name) {
+		return "", false
+	}
+	if strings.HasSuffix(flag.Value.Type(), "Slice") {
+		// slice flags parse their value as a CSV record, so values containing commas have to be quoted
+		return formatCsvRecord(source.GetStringSlice(flag.Name)), true
+	}
+	return source.GetString(flag.Name), true
+}
+
+func formatCsvRecord(values []string) string {
+	var record strings.Builder
+	writer := csv.NewWriter(&record)
+	_ = writer.Write(values)
+	writer.Flush()
+	return strings.TrimSuffix(record.String(), "\n")
+}
+
+func environmentVariableName(flagName string) string {
+	return "JPS_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// loadConfiguredPolicy returns the policy given via --policy or configured inline, nil if there is none.
+func loadConfiguredPolicy() (*Policy, error) {
+	if policyFile != "" {
+		return loadPolicy(policyFile)
+	}
+	return policyRules, nil
+}
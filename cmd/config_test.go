@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func Test_applyConfigurationPrecedence(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	savedProfile, savedPolicyRules := profileName, policyRules
+	defer func() { profileName, policyRules = savedProfile, savedPolicyRules }()
+
+	var policy, outputDirectory, knownBuilds string
+	var rootPaths, excludePaths []string
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&policy, "policy", "", "")
+	cmd.Flags().StringVar(&outputDirectory, "output-dir", ".", "")
+	cmd.Flags().StringVar(&knownBuilds, "known-builds", "", "")
+	cmd.Flags().StringSliceVar(&rootPaths, "scan-file-system-root-paths", []string{"/usr/lib/jvm"}, "")
+	cmd.Flags().StringSliceVar(&excludePaths, "scan-file-system-exclude-paths", nil, "")
+	if err := cmd.Flags().Set("policy", "flag.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	// config file
+	viper.Set("policy", "config.yaml")
+	viper.Set("output-dir", "/var/config")
+	viper.Set("known-builds", "config-builds.json")
+	viper.Set("scan-file-system-root-paths", []string{"/config"})
+	viper.Set("scan-file-system-exclude-paths", []string{`/opt/a{1,2}/`, `/opt/"quoted"/`})
+	viper.Set("profile", "ci")
+	viper.Set("profiles", map[string]interface{}{"ci": map[string]interface{}{
+		"output-dir":                  "/var/profile",
+		"scan-file-system-root-paths": []string{"/profile"},
+	}})
+	// environment
+	t.Setenv("JPS_OUTPUT_DIR", "/var/env")
+
+	if err := applyConfiguration(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if policy != "flag.yaml" {
+		t.Errorf("policy = %s, want the flag value", policy)
+	}
+	if outputDirectory != "/var/env" {
+		t.Errorf("output-dir = %s, want the environment value", outputDirectory)
+	}
+	if !reflect.DeepEqual(rootPaths, []string{"/profile"}) {
+		t.Errorf("scan-file-system-root-paths = %v, want the profile value", rootPaths)
+	}
+	if knownBuilds != "config-builds.json" {
+		t.Errorf("known-builds = %s, want the config file value", knownBuilds)
+	}
+	if want := []string{`/opt/a{1,2}/`, `/opt/"quoted"/`}; !reflect.DeepEqual(excludePaths, want) {
+		t.Errorf("scan-file-system-exclude-paths = %q, want %q", excludePaths, want)
+	}
+}
+
+func Test_loadProfileUnknown(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	if _, err := loadProfile("does-not-exist"); err == nil {
+		t.Errorf("loadProfile() returned no error for an unknown profile")
+	}
+}
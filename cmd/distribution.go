@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"strings"
+)
+
+type JvmImplementation int64
+
+const (
+	JvmUnknown JvmImplementation = iota
+	JvmHotSpot
+	JvmOpenJ9
+	JvmGraalVM
+	JvmZing
+)
+
+func (j JvmImplementation) String() string {
+	switch j {
+	case JvmUnknown:
+		return "unknown"
+	case JvmHotSpot:
+		return "hotspot"
+	case JvmOpenJ9:
+		return "openj9"
+	case JvmGraalVM:
+		return "graalvm"
+	case JvmZing:
+		return "zing"
+	}
+
+	return "unknown"
+}
+
+type Distribution int64
+
+const (
+	DistributionUnknown Distribution = iota
+	DistributionOracle
+	DistributionOracleOpenJDK
+	DistributionTemurin
+	DistributionCorretto
+	DistributionZulu
+	DistributionAzulPrime
+	DistributionLiberica
+	DistributionSemeru
+	DistributionRedHat
+	DistributionMicrosoft
+	DistributionSapMachine
+	DistributionJBR
+	DistributionGraalVMCE
+	DistributionGraalVMEE
+	DistributionIBM
+)
+
+func (d Distribution) String() string {
+	switch d {
+	case DistributionUnknown:
+		return "unknown"
+	case DistributionOracle:
+		return "oracle"
+	case DistributionOracleOpenJDK:
+		return "oracle-openjdk"
+	case DistributionTemurin:
+		return "temurin"
+	case DistributionCorretto:
+		return "corretto"
+	case DistributionZulu:
+		return "zulu"
+	case DistributionAzulPrime:
+		return "azul-prime"
+	case DistributionLiberica:
+		return "liberica"
+	case DistributionSemeru:
+		return "semeru"
+	case DistributionRedHat:
+		return "redhat"
+	case DistributionMicrosoft:
+		return "microsoft"
+	case DistributionSapMachine:
+		return "sapmachine"
+	case DistributionJBR:
+		return "jbr"
+	case DistributionGraalVMCE:
+		return "graalvm-ce"
+	case DistributionGraalVMEE:
+		return "graalvm-ee"
+	case DistributionIBM:
+		return "ibm"
+	}
+
+	return "unknown"
+}
+
+// classifyJvmImplementation derives the virtual machine from java.vm.name and java.vendor.version,
+// or from the JVM_VARIANT of the release file.
+func classifyJvmImplementation(info *JavaInfo) JvmImplementation {
+	vmName := strings.ToLower(info.VmName)
+	switch {
+	case strings.Contains(vmName, "j9"):
+		// "Eclipse OpenJ9 VM" as well as the former "IBM J9 VM"
+		return JvmOpenJ9
+	case strings.Contains(vmName, "zing") || strings.Contains(vmName, "prime"):
+		return JvmZing
+	case strings.Contains(vmName, "graalvm") || strings.Contains(info.VendorVersion, "GraalVM"):
+		return JvmGraalVM
+	case strings.Contains(vmName, "hotspot") || strings.Contains(vmName, "server vm") || strings.Contains(vmName, "client vm"):
+		return JvmHotSpot
+	}
+	return JvmUnknown
+}
+
+// classifyDistribution derives the distribution from the vendor specific java.vendor.version,
+// e.g. "Temurin-17.0.9+9", and falls back to java.vendor for versions without it (java 8 and older).
+func classifyDistribution(info *JavaInfo) Distribution {
+	vendorVersion := info.VendorVersion
+	vendor := info.Vendor
+	switch {
+	case strings.Contains(vendorVersion, "GraalVM CE") || strings.Contains(info.VmName, "GraalVM CE"):
+		return DistributionGraalVMCE
+	case strings.Contains(vendorVersion, "GraalVM") || strings.Contains(info.VmName, "GraalVM"):
+		// Oracle GraalVM, formerly GraalVM Enterprise Edition
+		return DistributionGraalVMEE
+	case strings.Contains(vendorVersion, "Temurin") || strings.Contains(vendor, "Adoptium") || strings.Contains(vendor, "AdoptOpenJDK"):
+		return DistributionTemurin
+	case strings.Contains(vendorVersion, "Corretto") || strings.HasPrefix(vendor, "Amazon"):
+		return DistributionCorretto
+	case strings.HasPrefix(vendor, "Azul") || strings.Contains(vendorVersion, "Zulu") || strings.Contains(vendorVersion, "Zing"):
+		if info.JvmImplementation == JvmZing || strings.Contains(vendorVersion, "Zing") || strings.Contains(vendorVersion, "Prime") {
+			return DistributionAzulPrime
+		}
+		return DistributionZulu
+	case strings.Contains(vendorVersion, "Liberica") || strings.HasPrefix(vendor, "BellSoft"):
+		return DistributionLiberica
+	case strings.Contains(info.RuntimeName, "Semeru") || strings.Contains(vendorVersion, "Semeru"):
+		return DistributionSemeru
+	case strings.HasPrefix(vendor, "IBM") || strings.HasPrefix(info.VmVendor, "IBM") || strings.Contains(info.VmName, "IBM J9"):
+		// the IBM SDK, Technology Edition, that names its runtime "Java(TM)" as the Oracle JDK does
+		return DistributionIBM
+	case strings.HasPrefix(vendor, "Red Hat"):
+		return DistributionRedHat
+	case strings.HasPrefix(vendor, "Microsoft"):
+		return DistributionMicrosoft
+	case strings.Contains(vendorVersion, "SapMachine") || strings.HasPrefix(vendor, "SAP"):
+		return DistributionSapMachine
+	case strings.Contains(vendorVersion, "JBR") || strings.HasPrefix(vendor, "JetBrains"):
+		return DistributionJBR
+	case isOracleJdk(info):
+		return DistributionOracle
+	case strings.HasPrefix(vendor, "Oracle"):
+		return DistributionOracleOpenJDK
+	}
+	return DistributionUnknown
+}
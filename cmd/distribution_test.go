@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func Test_classifyDistribution(t *testing.T) {
+	tests := []struct {
+		name             string
+		info             JavaInfo
+		wantDistribution Distribution
+		wantJvm          JvmImplementation
+	}{
+		{"Oracle JDK 8", JavaInfo{Vendor: "Oracle Corporation", RuntimeName: "Java(TM) SE Runtime Environment", VmName: "Java HotSpot(TM) 64-Bit Server VM"}, DistributionOracle, JvmHotSpot},
+		{"Oracle OpenJDK", JavaInfo{Vendor: "Oracle Corporation", RuntimeName: "OpenJDK Runtime Environment", VmName: "OpenJDK 64-Bit Server VM"}, DistributionOracleOpenJDK, JvmHotSpot},
+		{"Temurin", JavaInfo{Vendor: "Eclipse Adoptium", VendorVersion: "Temurin-17.0.9+9", VmName: "OpenJDK 64-Bit Server VM"}, DistributionTemurin, JvmHotSpot},
+		{"Corretto 8", JavaInfo{Vendor: "Amazon.com Inc.", VmName: "OpenJDK 64-Bit Server VM"}, DistributionCorretto, JvmHotSpot},
+		{"Zulu", JavaInfo{Vendor: "Azul Systems, Inc.", VendorVersion: "Zulu11.68+17-CA", VmName: "OpenJDK 64-Bit Server VM"}, DistributionZulu, JvmHotSpot},
+		{"Azul Prime", JavaInfo{Vendor: "Azul Systems, Inc.", VmName: "Zing 64-Bit Tiered VM"}, DistributionAzulPrime, JvmZing},
+		{"Semeru", JavaInfo{Vendor: "IBM Corporation", RuntimeName: "IBM Semeru Runtime Open Edition", VmName: "Eclipse OpenJ9 VM"}, DistributionSemeru, JvmOpenJ9},
+		{"IBM SDK 8", JavaInfo{Vendor: "IBM Corporation", RuntimeName: "Java(TM) SE Runtime Environment", VmName: "IBM J9 VM", VmVendor: "IBM Corporation"}, DistributionIBM, JvmOpenJ9},
+		{"IBM J9 VM with Oracle vendor", JavaInfo{Vendor: "Oracle Corporation", RuntimeName: "Java(TM) SE Runtime Environment", VmName: "IBM J9 VM"}, DistributionIBM, JvmOpenJ9},
+		{"Red Hat", JavaInfo{Vendor: "Red Hat, Inc.", VmName: "OpenJDK 64-Bit Server VM"}, DistributionRedHat, JvmHotSpot},
+		{"JBR", JavaInfo{Vendor: "JetBrains s.r.o.", VendorVersion: "JBR-17.0.5+1-653.14-jcef"}, DistributionJBR, JvmUnknown},
+		{"GraalVM CE", JavaInfo{Vendor: "GraalVM Community", VendorVersion: "GraalVM CE 21+35.1", VmName: "OpenJDK 64-Bit Server VM"}, DistributionGraalVMCE, JvmGraalVM},
+		{"Oracle GraalVM", JavaInfo{Vendor: "Oracle Corporation", VendorVersion: "Oracle GraalVM 21+35.1", VmName: "Java HotSpot(TM) 64-Bit Server VM"}, DistributionGraalVMEE, JvmGraalVM},
+		{"unknown", JavaInfo{Vendor: "Private Build"}, DistributionUnknown, JvmUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.info.JvmImplementation = classifyJvmImplementation(&tt.info)
+			if tt.info.JvmImplementation != tt.wantJvm {
+				t.Errorf("classifyJvmImplementation() = %v, want %v", tt.info.JvmImplementation, tt.wantJvm)
+			}
+			if got := classifyDistribution(&tt.info); got != tt.wantDistribution {
+				t.Errorf("classifyDistribution() = %v, want %v", got, tt.wantDistribution)
+			}
+		})
+	}
+}
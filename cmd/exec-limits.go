@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package cmd
+
+// applyResourceLimits is only supported on linux, other platforms rely on the timeout.
+func applyResourceLimits(pid int) error {
+	return nil
+}
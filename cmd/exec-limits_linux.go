@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"math"
+
+	"golang.org/x/sys/unix"
+)
+
+// applyResourceLimits limits the cpu time to the timeout and the number of open files and disables core dumps.
+// The limits are set right after the start, the executed binary may run without them for a moment.
+func applyResourceLimits(pid int) error {
+	limits := map[int]unix.Rlimit{
+		unix.RLIMIT_CORE:   {Cur: 0, Max: 0},
+		unix.RLIMIT_NOFILE: {Cur: 1024, Max: 1024},
+	}
+	if execTimeout > 0 {
+		cpuSeconds := uint64(math.Ceil(execTimeout.Seconds()))
+		limits[unix.RLIMIT_CPU] = unix.Rlimit{Cur: cpuSeconds, Max: cpuSeconds}
+	}
+	for resource, limit := range limits {
+		limit := limit
+		if err := unix.Prlimit(pid, resource, &limit, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
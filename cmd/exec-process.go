@@ -0,0 +1,56 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+import (
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// startInProcessGroup lets the command run in its own process group,
+// so that processes started by a java launcher script are killed along with it.
+func startInProcessGroup(command *exec.Cmd) {
+	if command.SysProcAttr == nil {
+		command.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	command.SysProcAttr.Setpgid = true
+}
+
+func killProcessGroup(command *exec.Cmd) {
+	_ = syscall.Kill(-command.Process.Pid, syscall.SIGKILL)
+}
+
+// runAsUser lets the command run with the user and group ids of the given user, which requires root privileges.
+func runAsUser(command *exec.Cmd, username string) error {
+	account, err := user.Lookup(username)
+	if err != nil {
+		return err
+	}
+	uid, err := strconv.ParseUint(account.Uid, 10, 32)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.ParseUint(account.Gid, 10, 32)
+	if err != nil {
+		return err
+	}
+	// the supplementary groups of the user replace the ones of root, they are dropped if they cannot be read
+	groups := []uint32{}
+	if groupIds, err := account.GroupIds(); err == nil {
+		for _, groupId := range groupIds {
+			if group, err := strconv.ParseUint(groupId, 10, 32); err == nil {
+				groups = append(groups, uint32(group))
+			}
+		}
+	} else {
+		log.Debugf("Cannot read the groups of user %s, running without supplementary groups: %s", username, err)
+	}
+	if command.SysProcAttr == nil {
+		command.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	command.SysProcAttr.Credential = &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid), Groups: groups}
+	return nil
+}
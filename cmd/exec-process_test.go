@@ -0,0 +1,45 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+import (
+	"os/exec"
+	"os/user"
+	"strconv"
+	"testing"
+)
+
+func Test_runAsUser(t *testing.T) {
+	account, err := user.Current()
+	if err != nil {
+		t.Skipf("current user not found: %v", err)
+	}
+	command := exec.Command("true")
+	if err := runAsUser(command, account.Username); err != nil {
+		t.Fatalf("runAsUser() error = %v", err)
+	}
+	credential := command.SysProcAttr.Credential
+	if strconv.FormatUint(uint64(credential.Uid), 10) != account.Uid || strconv.FormatUint(uint64(credential.Gid), 10) != account.Gid {
+		t.Errorf("runAsUser() uid = %d, gid = %d, want %s, %s", credential.Uid, credential.Gid, account.Uid, account.Gid)
+	}
+	// the groups of root must not be kept
+	if credential.NoSetGroups || credential.Groups == nil {
+		t.Errorf("runAsUser() NoSetGroups = %v, groups = %v, want the groups to be set", credential.NoSetGroups, credential.Groups)
+	}
+	if groupIds, err := account.GroupIds(); err == nil {
+		if len(credential.Groups) != len(groupIds) {
+			t.Errorf("runAsUser() groups = %v, want %v", credential.Groups, groupIds)
+		}
+		for i, groupId := range groupIds {
+			if i < len(credential.Groups) && strconv.FormatUint(uint64(credential.Groups[i]), 10) != groupId {
+				t.Errorf("runAsUser() groups = %v, want %v", credential.Groups, groupIds)
+				break
+			}
+		}
+	}
+
+	if err := runAsUser(exec.Command("true"), "no-such-user-of-java-scanner"); err == nil {
+		t.Errorf("runAsUser() of an unknown user succeeded")
+	}
+}
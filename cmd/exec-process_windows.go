@@ -0,0 +1,21 @@
+//go:build windows
+
+package cmd
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// startInProcessGroup does nothing on windows, only the java binary itself is killed on timeout.
+func startInProcessGroup(command *exec.Cmd) {
+}
+
+func killProcessGroup(command *exec.Cmd) {
+	_ = command.Process.Kill()
+}
+
+// runAsUser is not supported on windows, which would require the password of the user.
+func runAsUser(command *exec.Cmd, username string) error {
+	return errors.New("running java binaries as another user is not supported on windows")
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var noExec bool
+var sudoRetry bool
+var execTimeout time.Duration
+var execUser string
+
+var errExecTimeout = errors.New("killed after timeout")
+
+// killGracePeriod is how long a killed binary may take to exit.
+const killGracePeriod = 5 * time.Second
+
+// outputGracePeriod is how long processes started by a binary may keep its output open after it exited.
+const outputGracePeriod = time.Second
+
+type AnalysisMethod int64
+
+const (
+	NotAnalyzed AnalysisMethod = iota
+	Executed
+	ExecutedWithSudo
+	ReleaseFile
+	Cached
+)
+
+func (a AnalysisMethod) String() string {
+	switch a {
+	case NotAnalyzed:
+		return "not-analyzed"
+	case Executed:
+		return "executed"
+	case ExecutedWithSudo:
+		return "executed-with-sudo"
+	case ReleaseFile:
+		return "release-file"
+	case Cached:
+		return "cache"
+	}
+
+	return "unknown"
+}
+
+// environmentPassedToJavaBinaries are the only environment variables passed to executed java binaries.
+// Variables like JAVA_TOOL_OPTIONS or _JAVA_OPTIONS would change the output or even load agents.
+var environmentPassedToJavaBinaries = []string{"SystemRoot", "windir", "TEMP", "TMP", "TMPDIR"}
+
+// runJavaBinary executes a discovered java binary, that is not trusted.
+// It is killed after the --exec-timeout, runs with a minimal environment,
+// as the --exec-user if configured and with resource limits where supported.
+func runJavaBinary(name string, args ...string) ([]byte, error) {
+	ctx := context.Background()
+	if execTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, execTimeout)
+		defer cancel()
+	}
+
+	command := exec.Command(name, args...)
+	command.Env = minimalEnvironment()
+	startInProcessGroup(command)
+	if execUser != "" {
+		if err := runAsUser(command, execUser); err != nil {
+			return nil, err
+		}
+	}
+	// the output is read from a pipe, that is closed here, as processes started by the binary may keep it open
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	command.Stdout = writer
+	command.Stderr = writer
+	err = command.Start()
+	writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	var out lockedBuffer
+	copied := make(chan struct{})
+	go func() {
+		_, _ = out.ReadFrom(reader)
+		close(copied)
+	}()
+	if err := applyResourceLimits(command.Process.Pid); err != nil {
+		log.Warnf("Cannot limit the resources of %s: %s", name, err)
+	}
+
+	waited := make(chan error, 1)
+	go func() {
+		waited <- command.Wait()
+	}()
+	timedOut := false
+	select {
+	case err = <-waited:
+	case <-ctx.Done():
+		timedOut = true
+		// the whole process group is killed, a binary running as another user may not be killable though
+		killProcessGroup(command)
+		select {
+		case err = <-waited:
+		case <-time.After(killGracePeriod):
+			log.Warnf("Binary %s did not exit after being killed, not waiting for it anymore", name)
+		}
+	}
+	select {
+	case <-copied:
+	case <-time.After(outputGracePeriod):
+		log.Debugf("Output of %s is still open, not reading it anymore", name)
+	}
+	if timedOut {
+		return out.Bytes(), fmt.Errorf("%w of %s", errExecTimeout, execTimeout)
+	}
+	return out.Bytes(), err
+}
+
+// lockedBuffer is a buffer, that may be read while it is still written to.
+type lockedBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *lockedBuffer) ReadFrom(reader *os.File) (int64, error) {
+	var total int64
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := reader.Read(chunk)
+		if n > 0 {
+			b.mutex.Lock()
+			b.buffer.Write(chunk[:n])
+			b.mutex.Unlock()
+			total += int64(n)
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]byte(nil), b.buffer.Bytes()...)
+}
+
+func minimalEnvironment() []string {
+	var environment []string
+	for _, name := range environmentPassedToJavaBinaries {
+		if value, found := os.LookupEnv(name); found {
+			environment = append(environment, name+"="+value)
+		}
+	}
+	if runtime.GOOS != "windows" {
+		environment = append(environment, "LC_ALL=C")
+	}
+	return environment
+}
+
+// isSudoRetryAllowed checks whether a java binary, that failed to run, may be run again as root via 'sudo -n'.
+// The retry has to be enabled with --sudo and is not done when running as an unprivileged user has been configured.
+// A binary running as root cannot be killed by the scanner and its resources cannot be limited,
+// so 'timeout' is required to run it, unless the --exec-timeout is disabled.
+func isSudoRetryAllowed() bool {
+	if !sudoRetry || execUser != "" || runtime.GOOS == "windows" {
+		return false
+	}
+	if execTimeout > 0 {
+		if _, err := exec.LookPath("timeout"); err != nil {
+			log.Debugf("Not retrying java binaries via sudo, 'timeout' is not available to limit their run time: %s", err)
+			return false
+		}
+	}
+	return true
+}
+
+// sudoArguments returns the arguments of 'sudo' to run the java binary as root,
+// 'timeout' running as root kills it after the --exec-timeout.
+func sudoArguments(exe string, args ...string) []string {
+	sudoArgs := []string{"-n"}
+	if execTimeout > 0 {
+		seconds := int64((execTimeout + time.Second - 1) / time.Second)
+		sudoArgs = append(sudoArgs, "timeout", "-s", "KILL", strconv.FormatInt(seconds, 10))
+	}
+	sudoArgs = append(sudoArgs, exe)
+	return append(sudoArgs, args...)
+}
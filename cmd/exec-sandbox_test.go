@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_runJavaBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a posix shell")
+	}
+	t.Setenv("JAVA_TOOL_OPTIONS", "-javaagent:agent.jar")
+	defer func(timeout time.Duration) { execTimeout = timeout }(execTimeout)
+	execTimeout = time.Second
+
+	out, err := runJavaBinary("/bin/sh", "-c", "echo options=$JAVA_TOOL_OPTIONS")
+	if err != nil || strings.TrimSpace(string(out)) != "options=" {
+		t.Errorf("runJavaBinary() = %q, %v, want the environment variable not to be passed", out, err)
+	}
+
+	started := time.Now()
+	// the child process of the shell has to be killed as well
+	_, err = runJavaBinary("/bin/sh", "-c", "sleep 30; echo done")
+	if !errors.Is(err, errExecTimeout) || time.Since(started) > 10*time.Second {
+		t.Errorf("runJavaBinary() error = %v after %s, want a timeout", err, time.Since(started))
+	}
+
+	started = time.Now()
+	// a process started in the background keeps the output open after the shell exited
+	out, err = runJavaBinary("/bin/sh", "-c", "sleep 30 >&- 2>&- <&- & sleep 30 & echo started")
+	if err != nil || strings.TrimSpace(string(out)) != "started" || time.Since(started) > 10*time.Second {
+		t.Errorf("runJavaBinary() = %q, %v after %s, want the output without waiting for the background process", out, err, time.Since(started))
+	}
+}
+
+func Test_sudoArguments(t *testing.T) {
+	defer func(timeout time.Duration) { execTimeout = timeout }(execTimeout)
+	tests := []struct {
+		timeout time.Duration
+		want    []string
+	}{
+		{30 * time.Second, []string{"-n", "timeout", "-s", "KILL", "30", "/opt/jdk/bin/java", "-version"}},
+		{1500 * time.Millisecond, []string{"-n", "timeout", "-s", "KILL", "2", "/opt/jdk/bin/java", "-version"}},
+		{0, []string{"-n", "/opt/jdk/bin/java", "-version"}},
+	}
+	for _, tt := range tests {
+		execTimeout = tt.timeout
+		if got := sudoArguments("/opt/jdk/bin/java", "-version"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sudoArguments() with timeout %s = %v, want %v", tt.timeout, got, tt.want)
+		}
+	}
+}
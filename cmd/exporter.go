@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var exporterListenAddress string
+var exporterInterval time.Duration
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "run the activated detectors periodically and expose the findings as prometheus metrics",
+	Run: func(cmd *cobra.Command, args []string) {
+		Export()
+	},
+}
+
+func init() {
+	addDetectionFlags(exporterCmd.Flags())
+	exporterCmd.Flags().StringVarP(&exporterListenAddress, "listen", "l", ":9150", "address the metrics endpoint listens on")
+	exporterCmd.Flags().DurationVarP(&exporterInterval, "interval", "i", 15*time.Minute, "interval between two scans")
+
+	rootCmd.AddCommand(exporterCmd)
+}
+
+// scanMetrics holds the metrics of the latest scan and the error counters accumulated over all scans.
+type scanMetrics struct {
+	mutex           sync.RWMutex
+	scanned         bool
+	findings        []JavaInfo
+	scanDuration    time.Duration
+	scanTimestamp   time.Time
+	analyzeFailures map[DetectionMethod]int
+}
+
+func Export() {
+	if !isAnyDetectionMethodActivated() {
+		log.Fatalf("No detected methods configured! Use './java-scanner exporter --help' for a list of scanning options!")
+	}
+	logActivatedDetectionMethods()
+
+	metrics := &scanMetrics{analyzeFailures: map[DetectionMethod]int{}}
+	go func() {
+		for {
+			metrics.scan()
+			time.Sleep(exporterInterval)
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", metrics.handleMetrics)
+	log.Infof("Exporter listening on %s, scanning every %s...", exporterListenAddress, exporterInterval)
+	log.Fatal(http.ListenAndServe(exporterListenAddress, mux))
+}
+
+func (metrics *scanMetrics) scan() {
+	start := time.Now()
+	findings := runDetectors()
+	duration := time.Since(start)
+	logOverallResults(findings)
+
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metrics.scanned = true
+	metrics.findings = findings
+	metrics.scanDuration = duration
+	metrics.scanTimestamp = start
+	for _, info := range findings {
+		if info.ErrorText != "" && info.Component == "" {
+			metrics.analyzeFailures[info.DetectionMethod]++
+		}
+	}
+}
+
+func (metrics *scanMetrics) handleMetrics(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	metrics.mutex.RLock()
+	defer metrics.mutex.RUnlock()
+	metrics.write(writer)
+}
+
+// write renders the metrics in the prometheus text exposition format.
+func (metrics *scanMetrics) write(writer io.Writer) {
+	installations := map[string]int{}
+	runningJvms := map[string]int{}
+	for _, info := range metrics.findings {
+		if !isJavaInstallation(info) {
+			continue
+		}
+		major := strconv.Itoa(info.MajorVersion)
+		if info.DetectionMethod == RunningProcesses {
+			runningJvms[formatLabels("user", info.Username, "vendor", info.Vendor, "major", major)]++
+		} else {
+			installations[formatLabels("vendor", info.Vendor, "major", major, "distribution", info.Distribution.String(),
+				"license_category", info.LicenseCategory.String(), "detection_method", info.DetectionMethod.String())]++
+		}
+	}
+
+	writeMetricFamily(writer, "java_scanner_installations", "gauge",
+		"Number of valid java installations found by the latest scan.", installations)
+	writeMetricFamily(writer, "java_scanner_running_jvms", "gauge",
+		"Number of running java processes found by the latest scan.", runningJvms)
+
+	analyzeFailures := map[string]int{}
+	for method, count := range metrics.analyzeFailures {
+		analyzeFailures[formatLabels("detection_method", method.String())] = count
+	}
+	writeMetricFamily(writer, "java_scanner_analyze_errors_total", "counter",
+		"Number of findings, whose java binary could not be analyzed.", analyzeFailures)
+
+	if metrics.scanned {
+		fmt.Fprintf(writer, "# HELP java_scanner_scan_duration_seconds Duration of the latest scan.\n")
+		fmt.Fprintf(writer, "# TYPE java_scanner_scan_duration_seconds gauge\n")
+		fmt.Fprintf(writer, "java_scanner_scan_duration_seconds %g\n", metrics.scanDuration.Seconds())
+		fmt.Fprintf(writer, "# HELP java_scanner_last_scan_timestamp_seconds Start time of the latest scan.\n")
+		fmt.Fprintf(writer, "# TYPE java_scanner_last_scan_timestamp_seconds gauge\n")
+		fmt.Fprintf(writer, "java_scanner_last_scan_timestamp_seconds %d\n", metrics.scanTimestamp.Unix())
+	}
+}
+
+func writeMetricFamily(writer io.Writer, name string, metricType string, help string, samples map[string]int) {
+	fmt.Fprintf(writer, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(writer, "# TYPE %s %s\n", name, metricType)
+	labels := make([]string, 0, len(samples))
+	for label := range samples {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		fmt.Fprintf(writer, "%s%s %d\n", name, label, samples[label])
+	}
+}
+
+// formatLabels formats alternating label names and values as prometheus label set.
+func formatLabels(namesAndValues ...string) string {
+	labels := []string{}
+	for i := 0; i+1 < len(namesAndValues); i += 2 {
+		labels = append(labels, namesAndValues[i]+"=\""+escapeLabelValue(namesAndValues[i+1])+"\"")
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func Test_scanMetricsWrite(t *testing.T) {
+	metrics := &scanMetrics{
+		scanned:       true,
+		scanDuration:  1500 * time.Millisecond,
+		scanTimestamp: time.Unix(1700000000, 0),
+		findings: []JavaInfo{
+			{DetectionMethod: FileSystem, Valid: true, Vendor: "Eclipse Adoptium", MajorVersion: 17, Distribution: DistributionTemurin, LicenseCategory: LicenseOpenSource},
+			{DetectionMethod: FileSystem, Valid: true, Vendor: "Eclipse Adoptium", MajorVersion: 17, Distribution: DistributionTemurin, LicenseCategory: LicenseOpenSource},
+			{DetectionMethod: FileSystem, Valid: true, Vendor: "Oracle Corporation", MajorVersion: 8, Distribution: DistributionOracle, LicenseCategory: LicenseOracleOTN},
+			{DetectionMethod: FileSystem, Valid: false, ErrorText: "exec format error"},
+			{DetectionMethod: DesktopComponents, Valid: true, Vendor: "Oracle Corporation", MajorVersion: 8, Distribution: DistributionOracle, LicenseCategory: LicenseOracleOTN, Component: "java-web-start"},
+			{DetectionMethod: RunningProcesses, Valid: true, Username: `build "ci"\agent`, Vendor: "Eclipse Adoptium", MajorVersion: 17},
+		},
+		analyzeFailures: map[DetectionMethod]int{FileSystem: 3},
+	}
+	var out bytes.Buffer
+	metrics.write(&out)
+
+	want := `# HELP java_scanner_installations Number of valid java installations found by the latest scan.
+# TYPE java_scanner_installations gauge
+java_scanner_installations{vendor="Eclipse Adoptium",major="17",distribution="temurin",license_category="open-source",detection_method="file-system"} 2
+java_scanner_installations{vendor="Oracle Corporation",major="8",distribution="oracle",license_category="oracle-otn",detection_method="file-system"} 1
+# HELP java_scanner_running_jvms Number of running java processes found by the latest scan.
+# TYPE java_scanner_running_jvms gauge
+java_scanner_running_jvms{user="build \"ci\"\\agent",vendor="Eclipse Adoptium",major="17"} 1
+# HELP java_scanner_analyze_errors_total Number of findings, whose java binary could not be analyzed.
+# TYPE java_scanner_analyze_errors_total counter
+java_scanner_analyze_errors_total{detection_method="file-system"} 3
+# HELP java_scanner_scan_duration_seconds Duration of the latest scan.
+# TYPE java_scanner_scan_duration_seconds gauge
+java_scanner_scan_duration_seconds 1.5
+# HELP java_scanner_last_scan_timestamp_seconds Start time of the latest scan.
+# TYPE java_scanner_last_scan_timestamp_seconds gauge
+java_scanner_last_scan_timestamp_seconds 1700000000
+`
+	if got := out.String(); got != want {
+		t.Errorf("write() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func Test_escapeLabelValue(t *testing.T) {
+	if got := escapeLabelValue("a\\b\"c\nd"); got != `a\\b\"c\nd` {
+		t.Errorf("escapeLabelValue() = %s", got)
+	}
+}
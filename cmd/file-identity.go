@@ -0,0 +1,38 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+import (
+	"io/fs"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// fileIdentity identifies a file by the device and inode it is stored in.
+type fileIdentity struct {
+	Device uint64
+	Inode  uint64
+}
+
+func getFileIdentity(info fs.FileInfo) (fileIdentity, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileIdentity{}, false
+	}
+	return fileIdentity{Device: uint64(stat.Dev), Inode: uint64(stat.Ino)}, true
+}
+
+// getFileOwner returns the name of the user owning the file, its uid if the user is unknown.
+func getFileOwner(info fs.FileInfo) string {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+	uid := strconv.FormatUint(uint64(stat.Uid), 10)
+	if owner, err := user.LookupId(uid); err == nil {
+		return owner.Username
+	}
+	return uid
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package cmd
+
+import (
+	"io/fs"
+)
+
+// fileIdentity identifies a file by the device and inode it is stored in.
+type fileIdentity struct {
+	Device uint64
+	Inode  uint64
+}
+
+// getFileIdentity is not supported on windows, where file infos do not contain device and inode.
+func getFileIdentity(info fs.FileInfo) (fileIdentity, bool) {
+	return fileIdentity{}, false
+}
+
+// getFileOwner is not supported on windows, where the owner is part of the security descriptor of a file.
+func getFileOwner(info fs.FileInfo) string {
+	return ""
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// KnownBuild is an entry of the database of known vendor builds, identified by the SHA-256 of their java binary
+// or runtime archive (lib/modules or rt.jar).
+type KnownBuild struct {
+	SHA256       string
+	Vendor       string
+	Distribution string
+	Version      string
+	Platform     string
+}
+
+func (b KnownBuild) String() string {
+	return strings.TrimSpace(b.Distribution + " " + b.Version + " " + b.Platform)
+}
+
+// loadKnownBuilds reads a json array of known builds and indexes them by their lower case SHA-256.
+func loadKnownBuilds(fileName string) (map[string]KnownBuild, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var builds []KnownBuild
+	if err = json.Unmarshal(content, &builds); err != nil {
+		return nil, err
+	}
+	knownBuilds := map[string]KnownBuild{}
+	for _, build := range builds {
+		knownBuilds[strings.ToLower(build.SHA256)] = build
+	}
+	return knownBuilds, nil
+}
+
+// fingerprintFindings adds the fingerprints of the java binaries and runtime archives to all findings of binaries on disk.
+func fingerprintFindings(findings []JavaInfo) {
+	var knownBuilds map[string]KnownBuild
+	if knownBuildsFile != "" {
+		var err error
+		if knownBuilds, err = loadKnownBuilds(knownBuildsFile); err != nil {
+			log.Errorf("Cannot read known builds from %s: %s", knownBuildsFile, err)
+		}
+	}
+	// the same binary is found by several detection methods, it is hashed once
+	fingerprinted := map[string]JavaInfo{}
+	for i := range findings {
+		info := &findings[i]
+		if info.Exe == "" || info.ArchivePath != "" {
+			continue
+		}
+		if done, found := fingerprinted[info.Exe]; found {
+			copyFingerprint(info, done)
+			continue
+		}
+		fingerprintJavaInstallation(info, knownBuilds)
+		fingerprinted[info.Exe] = *info
+	}
+}
+
+func fingerprintJavaInstallation(info *JavaInfo, knownBuilds map[string]KnownBuild) {
+	stat, err := os.Stat(info.Exe)
+	if err != nil {
+		log.Warnf("Cannot fingerprint java binary %s: %s", info.Exe, err)
+		return
+	}
+	info.ExeSize = stat.Size()
+	info.ExeModTime = stat.ModTime()
+	info.ExeOwner = getFileOwner(stat)
+	if info.ExeSHA256, err = sha256File(info.Exe); err != nil {
+		log.Warnf("Cannot fingerprint java binary %s: %s", info.Exe, err)
+	}
+
+	info.RuntimeArchive = findRuntimeArchive(info.Exe)
+	if info.RuntimeArchive != "" {
+		if info.RuntimeArchiveSHA256, err = sha256File(info.RuntimeArchive); err != nil {
+			log.Warnf("Cannot fingerprint runtime archive %s: %s", info.RuntimeArchive, err)
+		}
+	}
+
+	for _, hash := range []string{info.ExeSHA256, info.RuntimeArchiveSHA256} {
+		if build, found := knownBuilds[hash]; found && hash != "" {
+			info.KnownBuild = build.String()
+			applyKnownBuild(info, build)
+			checkKnownBuild(info, build)
+			break
+		}
+	}
+}
+
+// applyKnownBuild takes the vendor and distribution from the known build, they identify the binary even if
+// its release file is missing or has been tampered with.
+func applyKnownBuild(info *JavaInfo, build KnownBuild) {
+	if build.Vendor != "" {
+		info.Vendor = build.Vendor
+	}
+	info.Distribution = knownBuildDistribution(build)
+}
+
+// knownBuildDistribution returns the distribution of a known build, that is given by its name like "temurin"
+// or "oracle-openjdk" or by a product name like "Eclipse Temurin".
+func knownBuildDistribution(build KnownBuild) Distribution {
+	name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(build.Distribution)), " ", "-")
+	for distribution := DistributionOracle; distribution <= DistributionIBM; distribution++ {
+		if distribution.String() == name {
+			return distribution
+		}
+	}
+	return classifyDistribution(&JavaInfo{Vendor: build.Vendor, VendorVersion: build.Distribution})
+}
+
+// checkKnownBuild fills in the version of a known build, if it could not be detected,
+// and warns about a detected version, that does not match the known build.
+func checkKnownBuild(info *JavaInfo, build KnownBuild) {
+	major, buildNumber := extractMajorAndBuildNumber(build.Version)
+	if major == 0 {
+		return
+	}
+	if info.MajorVersion == 0 {
+		info.MajorVersion, info.BuildNumber = major, buildNumber
+	} else if info.MajorVersion != major || info.BuildNumber != buildNumber {
+		log.Warnf("Java binary %s is the known build %s, but reports version %d build %d", info.Exe, build, info.MajorVersion, info.BuildNumber)
+	}
+}
+
+// findRuntimeArchive returns the archive containing the class library: lib/modules since java 9, rt.jar before.
+func findRuntimeArchive(javaBinary string) string {
+	resolved, err := filepath.EvalSymlinks(javaBinary)
+	if err != nil {
+		resolved = javaBinary
+	}
+	home := filepath.Dir(filepath.Dir(resolved))
+	for _, candidate := range []string{
+		filepath.Join(home, "lib", "modules"),
+		filepath.Join(home, "lib", "rt.jar"),
+		filepath.Join(home, "jre", "lib", "rt.jar"),
+	} {
+		if stat, err := os.Stat(candidate); err == nil && stat.Mode().IsRegular() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func sha256File(fileName string) (string, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func copyFingerprint(info *JavaInfo, from JavaInfo) {
+	info.ExeSHA256 = from.ExeSHA256
+	info.ExeSize = from.ExeSize
+	info.ExeOwner = from.ExeOwner
+	info.ExeModTime = from.ExeModTime
+	info.RuntimeArchive = from.RuntimeArchive
+	info.RuntimeArchiveSHA256 = from.RuntimeArchiveSHA256
+	info.KnownBuild = from.KnownBuild
+	if from.KnownBuild != "" {
+		info.Vendor, info.Distribution = from.Vendor, from.Distribution
+	}
+	if info.MajorVersion == 0 {
+		info.MajorVersion, info.BuildNumber = from.MajorVersion, from.BuildNumber
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// knownBinarySHA256 is the SHA-256 of the content "java binary of a known build\n".
+const knownBinarySHA256 = "23cb3d833edb70bdb8470edfb45b065c9e77f877531024170dbf9f8ca1551590"
+
+func Test_fingerprintFindingsKnownBuild(t *testing.T) {
+	home := t.TempDir()
+	javaBinary := filepath.Join(home, "bin", "java")
+	if err := os.MkdirAll(filepath.Dir(javaBinary), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(javaBinary, []byte("java binary of a known build\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	knownBuildsJson := `[{"SHA256": "` + knownBinarySHA256 + `", "Vendor": "Eclipse Adoptium", "Distribution": "Temurin",
+		"Version": "17.0.9+9", "Platform": "linux-x64"}]`
+	defer func(fileName string) { knownBuildsFile = fileName }(knownBuildsFile)
+	knownBuildsFile = filepath.Join(t.TempDir(), "known-builds.json")
+	if err := os.WriteFile(knownBuildsFile, []byte(knownBuildsJson), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the release file claims another vendor, the second finding is the same binary found by another detection method
+	findings := []JavaInfo{
+		{Exe: javaBinary, DetectionMethod: FileSystem, Vendor: "Oracle Corporation"},
+		{Exe: javaBinary, DetectionMethod: RunningProcesses},
+	}
+	fingerprintFindings(findings)
+	for _, info := range findings {
+		if info.ExeSHA256 != knownBinarySHA256 || info.KnownBuild != "Temurin 17.0.9+9 linux-x64" {
+			t.Errorf("fingerprintFindings() = %s, %q, want the known build", info.ExeSHA256, info.KnownBuild)
+		}
+		if info.Vendor != "Eclipse Adoptium" || info.Distribution != DistributionTemurin {
+			t.Errorf("fingerprintFindings() vendor = %q, distribution = %v, want the ones of the known build", info.Vendor, info.Distribution)
+		}
+		if info.MajorVersion != 17 || info.BuildNumber != 9 {
+			t.Errorf("fingerprintFindings() version = %d build %d, want 17 build 9", info.MajorVersion, info.BuildNumber)
+		}
+	}
+}
+
+func Test_knownBuildDistribution(t *testing.T) {
+	tests := []struct {
+		build KnownBuild
+		want  Distribution
+	}{
+		{KnownBuild{Distribution: "temurin"}, DistributionTemurin},
+		{KnownBuild{Distribution: "Oracle OpenJDK"}, DistributionOracleOpenJDK},
+		{KnownBuild{Distribution: "graalvm-ce"}, DistributionGraalVMCE},
+		{KnownBuild{Distribution: "Eclipse Temurin"}, DistributionTemurin},
+		{KnownBuild{Distribution: "Amazon Corretto"}, DistributionCorretto},
+		{KnownBuild{Vendor: "Azul Systems, Inc.", Distribution: "OpenJDK"}, DistributionZulu},
+		{KnownBuild{Distribution: "OpenJDK"}, DistributionUnknown},
+	}
+	for _, tt := range tests {
+		if got := knownBuildDistribution(tt.build); got != tt.want {
+			t.Errorf("knownBuildDistribution(%+v) = %v, want %v", tt.build, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"strings"
+)
+
+type LicenseCategory int64
+
+const (
+	LicenseUnknown LicenseCategory = iota
+	LicenseOpenSource
+	LicenseOracleBCL
+	LicenseOracleOTN
+	LicenseOracleNFTC
+	LicenseOracleCommercialFeatures
+	LicenseOracleBundled
+	LicenseCommercial
+)
+
+func (l LicenseCategory) String() string {
+	switch l {
+	case LicenseUnknown:
+		return "unknown"
+	case LicenseOpenSource:
+		return "open-source"
+	case LicenseOracleBCL:
+		return "oracle-bcl"
+	case LicenseOracleOTN:
+		return "oracle-otn"
+	case LicenseOracleNFTC:
+		return "oracle-nftc"
+	case LicenseOracleCommercialFeatures:
+		return "oracle-commercial-features"
+	case LicenseOracleBundled:
+		return "oracle-bundled"
+	case LicenseCommercial:
+		return "commercial"
+	}
+
+	return "unknown"
+}
+
+// classifyLicense derives the license terms that apply to a finding from its vendor, runtime name and version.
+// Only Oracle JDK builds ("Java(TM) SE Runtime Environment" by Oracle) are subject to the Oracle license terms,
+// OpenJDK builds - including the ones published by Oracle - are open source.
+// "Java(TM)" runtimes of other vendors, e.g. the IBM SDK or Apple Java 6, are under their vendor's commercial terms.
+func classifyLicense(info *JavaInfo) LicenseCategory {
+	if info.Vendor == "" && info.RuntimeName == "" {
+		return LicenseUnknown
+	}
+	if !isOracleJdk(info) {
+		switch {
+		case isOpenSourceRuntime(info):
+			return LicenseOpenSource
+		case strings.Contains(info.RuntimeName, "Java(TM)"):
+			return LicenseCommercial
+		}
+		return LicenseUnknown
+	}
+	switch {
+	case info.ApplicationName == "WebLogic":
+		// the WebLogic license includes the right to use Oracle Java SE for running WebLogic
+		return LicenseOracleBundled
+	case info.MajorVersion == 0:
+		return LicenseUnknown
+	case isCommercialFeatureLicensed(info) && isBinaryCodeLicensed(info):
+		// the Binary Code License excludes the commercial features from the free use
+		return LicenseOracleCommercialFeatures
+	case isBinaryCodeLicensed(info):
+		return LicenseOracleBCL
+	case info.MajorVersion < 17:
+		return LicenseOracleOTN
+	case info.MajorVersion == 17 && info.BuildNumber > 12:
+		// updates of 17 after 17.0.12 are released under the OTN again
+		return LicenseOracleOTN
+	}
+	return LicenseOracleNFTC
+}
+
+// isBinaryCodeLicensed checks for the Oracle JDK releases under the Binary Code License:
+// all releases up to 10 and the public updates of 8 up to 8u202. The OTN starts with 11 and 8u211.
+func isBinaryCodeLicensed(info *JavaInfo) bool {
+	if info.MajorVersion == 8 {
+		return info.BuildNumber <= 202
+	}
+	return info.MajorVersion < 11
+}
+
+// isOracleJdk checks for a "Java(TM)" runtime built by Oracle or, up to 6, by Sun.
+// Other licensees of the trademark, e.g. IBM or Apple, name their runtimes the same way.
+func isOracleJdk(info *JavaInfo) bool {
+	if !strings.Contains(info.RuntimeName, "Java(TM)") {
+		return false
+	}
+	return isOracleVendor(info.Vendor) || isOracleVendor(info.VmVendor)
+}
+
+func isOracleVendor(vendor string) bool {
+	return strings.HasPrefix(vendor, "Oracle") || strings.HasPrefix(vendor, "Sun Microsystems")
+}
+
+// isOpenSourceRuntime checks for the runtime names of OpenJDK builds, e.g. "OpenJDK Runtime Environment"
+// or "IBM Semeru Runtime Open Edition".
+func isOpenSourceRuntime(info *JavaInfo) bool {
+	return strings.Contains(info.RuntimeName, "OpenJDK") || strings.Contains(info.RuntimeName, "Open Edition")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func Test_classifyLicense(t *testing.T) {
+	tests := []struct {
+		name        string
+		vendor      string
+		runtimeName string
+		major       int
+		build       int
+		want        LicenseCategory
+	}{
+		{"nothing known", "", "", 0, 0, LicenseUnknown},
+		{"Oracle JDK 6", "Sun Microsystems Inc.", "Java(TM) SE Runtime Environment", 6, 45, LicenseOracleBCL},
+		{"Oracle JDK 8u202", "Oracle Corporation", "Java(TM) SE Runtime Environment", 8, 202, LicenseOracleBCL},
+		{"Oracle JDK 8u211", "Oracle Corporation", "Java(TM) SE Runtime Environment", 8, 211, LicenseOracleOTN},
+		{"Oracle JDK 9.0.4", "Oracle Corporation", "Java(TM) SE Runtime Environment", 9, 4, LicenseOracleBCL},
+		{"Oracle JDK 10.0.2", "Oracle Corporation", "Java(TM) SE Runtime Environment", 10, 2, LicenseOracleBCL},
+		{"Oracle JDK 11", "Oracle Corporation", "Java(TM) SE Runtime Environment", 11, 3, LicenseOracleOTN},
+		{"Oracle JDK 17.0.5", "Oracle Corporation", "Java(TM) SE Runtime Environment", 17, 5, LicenseOracleNFTC},
+		{"Oracle JDK 17.0.13", "Oracle Corporation", "Java(TM) SE Runtime Environment", 17, 13, LicenseOracleOTN},
+		{"Oracle OpenJDK 11", "Oracle Corporation", "OpenJDK Runtime Environment", 11, 2, LicenseOpenSource},
+		{"Temurin 8", "Temurin", "OpenJDK Runtime Environment", 8, 392, LicenseOpenSource},
+		{"IBM Semeru 17", "IBM Corporation", "IBM Semeru Runtime Open Edition", 17, 8, LicenseOpenSource},
+		{"IBM SDK 8", "IBM Corporation", "Java(TM) SE Runtime Environment", 8, 0, LicenseCommercial},
+		{"Apple Java 6", "Apple Inc.", "Java(TM) SE Runtime Environment", 6, 65, LicenseCommercial},
+		{"unknown runtime", "Example Corp.", "Example Runtime Environment", 11, 0, LicenseUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := JavaInfo{Vendor: tt.vendor, RuntimeName: tt.runtimeName, MajorVersion: tt.major, BuildNumber: tt.build}
+			if got := classifyLicense(&info); got != tt.want {
+				t.Errorf("classifyLicense() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_classifyLicenseOfBundledRuntime(t *testing.T) {
+	info := JavaInfo{Vendor: "Oracle Corporation", RuntimeName: "Java(TM) SE Runtime Environment", MajorVersion: 8, BuildNumber: 391, ApplicationName: "WebLogic"}
+	if got := classifyLicense(&info); got != LicenseOracleBundled {
+		t.Errorf("classifyLicense() of Oracle JDK running WebLogic = %v, want %v", got, LicenseOracleBundled)
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var detectMacOSJavaVirtualMachines bool
+
+// javaVirtualMachinesDirectories are the directories JDK installers on macOS put their bundles into,
+// besides ~/Library/Java/JavaVirtualMachines of each user, which is used by IntelliJ IDEA and SDK managers.
+var javaVirtualMachinesDirectories = []string{"/Library/Java/JavaVirtualMachines", "/System/Library/Java/JavaVirtualMachines"}
+
+// javaVirtualMachine is a JDK bundle described by its Info.plist.
+type javaVirtualMachine struct {
+	Home             string
+	Source           string
+	BundleIdentifier string
+	Name             string
+	Vendor           string
+	Version          string
+}
+
+// macOSJavaVirtualMachineFindings analyzes the java virtual machines, falling back to the static information
+// of their Info.plist, if the java binary cannot be analyzed.
+func macOSJavaVirtualMachineFindings(jvms []javaVirtualMachine) []JavaInfo {
+	var result []JavaInfo
+	scanTimestamp := time.Now()
+	hostname, _ := os.Hostname()
+	analyzed := map[string]JavaInfo{}
+	for _, jvm := range jvms {
+		info := analyzeJavaBinaryOnce(analyzed, filepath.Join(jvm.Home, "bin", "java"))
+		info.DetectionMethod = MacOSJavaVirtualMachines
+		info.ScanTimestamp = scanTimestamp
+		info.Hostname = hostname
+		info.ConfigSource = jvm.Source
+		info.ProductCode = jvm.BundleIdentifier
+		info.ProductName = jvm.Name
+		info.Publisher = jvm.Vendor
+		if info.Vendor == "" {
+			info.Vendor = jvm.Vendor
+		}
+		if info.MajorVersion == 0 && jvm.Version != "" {
+			info.MajorVersion, info.BuildNumber = extractMajorAndBuildNumber(jvm.Version)
+		}
+		log.Infof("Found java virtual machine %s %s in %s", jvm.BundleIdentifier, jvm.Version, jvm.Home)
+		result = append(result, info)
+	}
+	log.Infof("number of detected java virtual machines: %d!", len(result))
+	return result
+}
+
+// findJavaVirtualMachines returns the *.jdk bundles of the directories.
+func findJavaVirtualMachines(directories []string) []javaVirtualMachine {
+	var jvms []javaVirtualMachine
+	for _, directory := range directories {
+		bundles, _ := filepath.Glob(filepath.Join(directory, "*.jdk"))
+		for _, bundle := range bundles {
+			jvm, err := readJavaVirtualMachine(bundle)
+			if err != nil {
+				// the home is reported with the information, that could be read
+				if stat, statErr := os.Stat(jvm.Home); statErr != nil || !stat.IsDir() {
+					log.Warnf("Cannot read java virtual machine %s: %s", bundle, err)
+					continue
+				}
+				log.Warnf("Cannot read the Info.plist of java virtual machine %s, reporting its home only: %s", bundle, err)
+			}
+			jvms = append(jvms, jvm)
+		}
+	}
+	return jvms
+}
+
+// readJavaVirtualMachine reads Contents/Info.plist of a JDK bundle. The home of the bundle is returned
+// even if the Info.plist cannot be read, e.g. because it is a binary property list.
+func readJavaVirtualMachine(bundle string) (javaVirtualMachine, error) {
+	source := filepath.Join(bundle, "Contents", "Info.plist")
+	jvm := javaVirtualMachine{Home: filepath.Join(bundle, "Contents", "Home"), Source: source}
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return jvm, err
+	}
+	plist, err := parsePlist(content)
+	if err != nil {
+		return jvm, err
+	}
+	info, ok := plist.(map[string]interface{})
+	if !ok {
+		return jvm, fmt.Errorf("%s is no dict", source)
+	}
+	jvm.BundleIdentifier = plistString(info, "CFBundleIdentifier")
+	jvm.Name = plistString(info, "CFBundleName")
+	if javaVM, ok := info["JavaVM"].(map[string]interface{}); ok {
+		jvm.Vendor = plistString(javaVM, "JVMVendor")
+		jvm.Version = plistString(javaVM, "JVMVersion")
+		if jvm.Version == "" {
+			jvm.Version = plistString(javaVM, "JVMPlatformVersion")
+		}
+	}
+	return jvm, nil
+}
+
+// parseJavaHomeList parses the output of 'java_home -X', the property list of all java virtual machines
+// registered on the system, including the ones outside the JavaVirtualMachines directories.
+func parseJavaHomeList(content []byte) ([]javaVirtualMachine, error) {
+	plist, err := parsePlist(content)
+	if err != nil {
+		return nil, err
+	}
+	entries, ok := plist.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("java_home -X returned no array")
+	}
+	var jvms []javaVirtualMachine
+	for _, entry := range entries {
+		dict, ok := entry.(map[string]interface{})
+		if !ok || plistString(dict, "JVMHomePath") == "" {
+			continue
+		}
+		jvms = append(jvms, javaVirtualMachine{Home: plistString(dict, "JVMHomePath"), Source: "java_home",
+			BundleIdentifier: plistString(dict, "JVMBundleID"), Name: plistString(dict, "JVMName"),
+			Vendor: plistString(dict, "JVMVendor"), Version: plistString(dict, "JVMVersion")})
+	}
+	return jvms, nil
+}
+
+// mergeJavaVirtualMachines appends the java virtual machines of java_home, whose home is not among the bundles found.
+func mergeJavaVirtualMachines(bundles []javaVirtualMachine, registered []javaVirtualMachine) []javaVirtualMachine {
+	homes := map[string]bool{}
+	for _, jvm := range bundles {
+		homes[filepath.Clean(jvm.Home)] = true
+	}
+	for _, jvm := range registered {
+		if !homes[filepath.Clean(jvm.Home)] {
+			homes[filepath.Clean(jvm.Home)] = true
+			bundles = append(bundles, jvm)
+		}
+	}
+	return bundles
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const temurinInfoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleIdentifier</key>
+	<string>net.temurin.17.jdk</string>
+	<key>CFBundleName</key>
+	<string>Eclipse Temurin 17</string>
+	<key>JavaVM</key>
+	<dict>
+		<key>JVMPlatformVersion</key>
+		<string>17.0.9</string>
+		<key>JVMVendor</key>
+		<string>Eclipse Adoptium</string>
+		<key>JVMVersion</key>
+		<string>17.0.9+9</string>
+	</dict>
+</dict>
+</plist>`
+
+func Test_findJavaVirtualMachines(t *testing.T) {
+	directory := t.TempDir()
+	bundle := filepath.Join(directory, "temurin-17.jdk")
+	if err := os.MkdirAll(filepath.Join(bundle, "Contents", "Home", "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bundle, "Contents", "Info.plist"), []byte(temurinInfoPlist), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// bundles with an unreadable or without Info.plist are reported with their home, unless the home is missing
+	writeFixtureFiles(t, directory, map[string]string{
+		"binary.jdk/Contents/Info.plist":     "bplist00\xd1\x01\x02",
+		"binary.jdk/Contents/Home/bin/java":  "",
+		"missing.jdk/Contents/Home/bin/java": "",
+		"no-dict.jdk/Contents/Info.plist":    "<plist><array><string>17</string></array></plist>",
+		"no-dict.jdk/Contents/Home/bin/java": "",
+		"broken.jdk/Contents/Info.plist":     "<plist><dict>",
+	})
+
+	bundleOf := func(name string) javaVirtualMachine {
+		return javaVirtualMachine{Home: filepath.Join(directory, name, "Contents", "Home"), Source: filepath.Join(directory, name, "Contents", "Info.plist")}
+	}
+	want := []javaVirtualMachine{bundleOf("binary.jdk"), bundleOf("missing.jdk"), bundleOf("no-dict.jdk"),
+		{Home: filepath.Join(bundle, "Contents", "Home"), Source: filepath.Join(bundle, "Contents", "Info.plist"),
+			BundleIdentifier: "net.temurin.17.jdk", Name: "Eclipse Temurin 17", Vendor: "Eclipse Adoptium", Version: "17.0.9+9"}}
+	if got := findJavaVirtualMachines([]string{directory, filepath.Join(directory, "missing")}); !reflect.DeepEqual(got, want) {
+		t.Errorf("findJavaVirtualMachines() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_parseJavaHomeList(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<array>
+	<dict>
+		<key>JVMArch</key>
+		<string>arm64</string>
+		<key>JVMBundleID</key>
+		<string>com.azul.zulu.21.jdk</string>
+		<key>JVMEnabled</key>
+		<true/>
+		<key>JVMHomePath</key>
+		<string>/Library/Java/JavaVirtualMachines/zulu-21.jdk/Contents/Home</string>
+		<key>JVMName</key>
+		<string>Zulu 21.30.15</string>
+		<key>JVMPlatformVersion</key>
+		<string>21.0.1</string>
+		<key>JVMVendor</key>
+		<string>Azul Systems, Inc.</string>
+		<key>JVMVersion</key>
+		<string>21.0.1</string>
+	</dict>
+</array>
+</plist>`
+	got, err := parseJavaHomeList([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []javaVirtualMachine{{Home: "/Library/Java/JavaVirtualMachines/zulu-21.jdk/Contents/Home", Source: "java_home",
+		BundleIdentifier: "com.azul.zulu.21.jdk", Name: "Zulu 21.30.15", Vendor: "Azul Systems, Inc.", Version: "21.0.1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseJavaHomeList() = %+v, want %+v", got, want)
+	}
+}
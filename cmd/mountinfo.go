@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// mountInfoFile lists the mounts of the current process on linux.
+const mountInfoFile = "/proc/self/mountinfo"
+
+// defaultSkipMountTypes are pseudo, container overlay and network file systems, which are not scanned by default.
+var defaultSkipMountTypes = []string{
+	"proc", "sysfs", "devtmpfs", "devpts", "cgroup", "cgroup2", "securityfs", "debugfs", "tracefs", "pstore",
+	"bpf", "configfs", "fusectl", "mqueue", "hugetlbfs", "autofs", "binfmt_misc", "nsfs", "rpc_pipefs",
+	"overlay", "aufs",
+	"nfs", "nfs4", "cifs", "smb3", "smbfs", "afs", "ceph", "fuse.sshfs", "fuse.glusterfs", "glusterfs", "lustre", "9p",
+}
+
+type mountPoint struct {
+	Path   string
+	FSType string
+	Source string
+}
+
+// readMountPoints returns the mount points of this host, none if they cannot be determined on this platform.
+func readMountPoints() []mountPoint {
+	content, err := os.ReadFile(mountInfoFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf("Cannot read mount points from %s: %s", mountInfoFile, err)
+		}
+		return nil
+	}
+	return parseMountInfo(string(content))
+}
+
+// parseMountInfo parses the lines of /proc/self/mountinfo:
+// the mount point is the 5th field, file system type and source follow the '-' separator after the optional fields.
+func parseMountInfo(content string) []mountPoint {
+	var mountPoints []mountPoint
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		separator := -1
+		for i := 5; i < len(fields); i++ {
+			if fields[i] == "-" {
+				separator = i
+				break
+			}
+		}
+		if separator < 0 || separator+2 >= len(fields) {
+			continue
+		}
+		mountPoints = append(mountPoints, mountPoint{
+			Path:   unescapeMountInfo(fields[4]),
+			FSType: fields[separator+1],
+			Source: unescapeMountInfo(fields[separator+2]),
+		})
+	}
+	return mountPoints
+}
+
+// unescapeMountInfo replaces the octal escapes of space, tab, newline and backslash.
+func unescapeMountInfo(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var unescaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+4 <= len(value) {
+			if code, err := strconv.ParseUint(value[i+1:i+4], 8, 8); err == nil {
+				unescaped.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+		unescaped.WriteByte(value[i])
+	}
+	return unescaped.String()
+}
@@ -1,39 +1,91 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
 func createCsvFile(overallResult []JavaInfo) {
 	//timestampLayout := time.RFC3339
 	timestampLayout := "2006-01-02_15-04-05"
-	filename := fmt.Sprintf("result_%v.csv", time.Now().Format(timestampLayout))
+	filename := filepath.Join(outputDir, fmt.Sprintf("result_%v.csv", time.Now().Format(timestampLayout)))
 	csvFile, err := os.Create(filename)
 	if err != nil {
 		log.Fatalf("failed creating file: %s", err)
 	}
 	csvwriter := csv.NewWriter(csvFile)
 
-	_ = csvwriter.Write([]string{"DetectionMethod", "ScanTimestamp", "Hostname", "Exe", "Valid", "Username", "Vendor", "RuntimeName", "MajorVersion", "BuildNumber", "Error Text"})
+	header := []string{"DetectionMethod", "ScanTimestamp", "Hostname", "Exe", "ArchivePath", "ArchiveInnerPath", "Valid", "AnalysisMethod", "Username", "Vendor", "RuntimeName", "MajorVersion", "BuildNumber",
+		"Distribution", "JvmImplementation", "VmName", "VmVendor", "VmVersion", "VendorVersion", "SpecificationVersion", "OsArch",
+		"BinaryOS", "BinaryArch", "BinaryBits", "LicenseCategory",
+		"ExeSHA256", "ExeSize", "ExeOwner", "ExeModTime", "RuntimeArchive", "RuntimeArchiveSHA256", "KnownBuild", "CommercialFeatures", "ApplicationName", "ApplicationVersion", "Component", "ComponentPath", "Settings", "ConfigSource", "ServiceName", "ServiceState", "RequestedVersion", "RequestedVendor", "MatchingRuntime", "ToolName", "InstallSource", "ProductCode", "ProductName", "Publisher", "InstallDate", "Error Text"}
+	_ = csvwriter.Write(append(header, csvPropertyColumns...))
 	for _, infoRow := range overallResult {
-		_ = csvwriter.Write([]string{
+		row := []string{
 			infoRow.DetectionMethod.String(),
 			infoRow.ScanTimestamp.Format(timestampLayout),
 			infoRow.Hostname,
 			infoRow.Exe,
+			infoRow.ArchivePath,
+			infoRow.ArchiveInnerPath,
 			strconv.FormatBool(infoRow.Valid),
+			infoRow.AnalysisMethod.String(),
 			infoRow.Username,
 			infoRow.Vendor,
 			infoRow.RuntimeName,
 			strconv.Itoa(infoRow.MajorVersion),
 			strconv.Itoa(infoRow.BuildNumber),
+			infoRow.Distribution.String(),
+			infoRow.JvmImplementation.String(),
+			infoRow.VmName,
+			infoRow.VmVendor,
+			infoRow.VmVersion,
+			infoRow.VendorVersion,
+			infoRow.SpecificationVersion,
+			infoRow.OsArch,
+			infoRow.BinaryOS,
+			infoRow.BinaryArch,
+			formatBinaryBits(infoRow.BinaryBits),
+			infoRow.LicenseCategory.String(),
+			infoRow.ExeSHA256,
+			formatExeSize(infoRow.ExeSize),
+			infoRow.ExeOwner,
+			formatModTime(infoRow.ExeModTime, timestampLayout),
+			infoRow.RuntimeArchive,
+			infoRow.RuntimeArchiveSHA256,
+			infoRow.KnownBuild,
+			strings.Join(infoRow.CommercialFeatures, ";"),
+			infoRow.ApplicationName,
+			infoRow.ApplicationVersion,
+			infoRow.Component,
+			infoRow.ComponentPath,
+			formatSettings(infoRow.Settings),
+			infoRow.ConfigSource,
+			infoRow.ServiceName,
+			infoRow.ServiceState,
+			infoRow.RequestedVersion,
+			infoRow.RequestedVendor,
+			infoRow.MatchingRuntime,
+			infoRow.ToolName,
+			infoRow.InstallSource,
+			infoRow.ProductCode,
+			infoRow.ProductName,
+			infoRow.Publisher,
+			infoRow.InstallDate,
 			infoRow.ErrorText,
-		})
+		}
+		for _, property := range csvPropertyColumns {
+			row = append(row, infoRow.Properties[property])
+		}
+		_ = csvwriter.Write(row)
 	}
 	csvwriter.Flush()
 	err = csvFile.Close()
@@ -45,10 +97,32 @@ func createCsvFile(overallResult []JavaInfo) {
 
 }
 
+func formatBinaryBits(bits int) string {
+	if bits == 0 {
+		return ""
+	}
+	return strconv.Itoa(bits)
+}
+
+// formatExeSize leaves the size of findings, that have not been fingerprinted, empty.
+func formatExeSize(size int64) string {
+	if size == 0 {
+		return ""
+	}
+	return strconv.FormatInt(size, 10)
+}
+
+func formatModTime(modTime time.Time, layout string) string {
+	if modTime.IsZero() {
+		return ""
+	}
+	return modTime.Format(layout)
+}
+
 func addInfoToFindingsJson(infoList []JavaInfo) {
 	var err error
 	var findingsFile *os.File
-	fileName := "findings.log"
+	fileName := filepath.Join(outputDir, "findings.log")
 	findingsFile, err = os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
 	if err != nil {
 		panic(err)
@@ -74,12 +148,38 @@ func addInfoToFindingsJson(infoList []JavaInfo) {
 
 }
 
+// pushFindings sends the findings of this scan to a collector started via 'java-scanner collect'.
+func pushFindings(collectorUrl string, infoList []JavaInfo) error {
+	hostname, _ := os.Hostname()
+	batch := FindingsBatch{Hostname: hostname, Findings: infoList}
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	response, err := pushClient.Post(collectorFindingsUrl(collectorUrl), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("collector responded with status %s", response.Status)
+	}
+	log.Infof("Pushed %d findings to collector %s", len(infoList), collectorUrl)
+	return nil
+}
+
 func logOverallResults(overallResult []JavaInfo) {
 	countValid := 0
 	for _, javaInfo := range overallResult {
-		if javaInfo.Valid {
+		if isJavaInstallation(javaInfo) {
 			countValid++
 		}
 	}
 	log.Infof("Overall-results: detected %d valid java installations!", countValid)
+	for _, notScanned := range notScannedPaths {
+		log.Warnf("Overall-results: %s did not scan %s: %s", notScanned.DetectionMethod, notScanned.Path, notScanned.Reason)
+	}
+	if len(notScannedPaths) > 0 {
+		log.Warnf("Overall-results: %d paths were not scanned!", len(notScannedPaths))
+	}
 }
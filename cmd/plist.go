@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errBinaryPlist = errors.New("binary property lists are not supported")
+
+// parsePlist parses an XML property list like Info.plist into its value: map[string]interface{} for a dict,
+// []interface{} for an array, string, int64, float64, bool, []byte for data and time.Time for a date.
+func parsePlist(content []byte) (interface{}, error) {
+	if bytes.HasPrefix(content, []byte("bplist")) {
+		return nil, errBinaryPlist
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = errors.New("no plist element")
+			}
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			if start.Name.Local != "plist" {
+				return nil, fmt.Errorf("unexpected element <%s>, expected <plist>", start.Name.Local)
+			}
+			return parsePlistValue(decoder, nextPlistElement)
+		}
+	}
+}
+
+// nextPlistElement marks, that the next start element has to be read.
+var nextPlistElement = xml.StartElement{}
+
+// parsePlistValue parses the value of the given start element or, for nextPlistElement, of the next start element.
+func parsePlistValue(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	if start.Name.Local == "" {
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			if element, ok := token.(xml.StartElement); ok {
+				start = element
+				break
+			}
+		}
+	}
+	switch start.Name.Local {
+	case "dict":
+		return parsePlistDict(decoder)
+	case "array":
+		return parsePlistArray(decoder)
+	case "true", "false":
+		if err := decoder.Skip(); err != nil {
+			return nil, err
+		}
+		return start.Name.Local == "true", nil
+	}
+	var text string
+	if err := decoder.DecodeElement(&text, &start); err != nil {
+		return nil, err
+	}
+	switch start.Name.Local {
+	case "string":
+		return text, nil
+	case "integer":
+		return strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	case "real":
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	case "date":
+		return time.Parse(time.RFC3339, strings.TrimSpace(text))
+	case "data":
+		return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+	}
+	return nil, fmt.Errorf("unknown plist element <%s>", start.Name.Local)
+}
+
+func parsePlistDict(decoder *xml.Decoder) (map[string]interface{}, error) {
+	dict := map[string]interface{}{}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch element := token.(type) {
+		case xml.EndElement:
+			return dict, nil
+		case xml.StartElement:
+			if element.Name.Local != "key" {
+				return nil, fmt.Errorf("unexpected element <%s> in dict, expected <key>", element.Name.Local)
+			}
+			var key string
+			if err := decoder.DecodeElement(&key, &element); err != nil {
+				return nil, err
+			}
+			if dict[key], err = parsePlistValue(decoder, nextPlistElement); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func parsePlistArray(decoder *xml.Decoder) ([]interface{}, error) {
+	array := []interface{}{}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch element := token.(type) {
+		case xml.EndElement:
+			return array, nil
+		case xml.StartElement:
+			value, err := parsePlistValue(decoder, element)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+	}
+}
+
+// plistString returns the string value of a key of a dict, an empty string if it is missing or no string.
+func plistString(dict map[string]interface{}, key string) string {
+	value, _ := dict[key].(string)
+	return value
+}
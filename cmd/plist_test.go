@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parsePlist(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleName</key>
+	<string>Eclipse Temurin 17 &amp; more</string>
+	<key>JavaVM</key>
+	<dict>
+		<key>JVMCapabilities</key>
+		<array>
+			<string>CommandLine</string>
+			<string>JNI</string>
+		</array>
+		<key>JVMPlatformVersion</key>
+		<real>17.0</real>
+		<key>JVMBuild</key>
+		<integer>9</integer>
+		<key>JVMEnabled</key>
+		<true/>
+		<key>JVMDebug</key>
+		<false/>
+		<key>JVMReleaseDate</key>
+		<date>2023-10-17T00:00:00Z</date>
+		<key>JVMData</key>
+		<data>SmF2YQ==</data>
+	</dict>
+</dict>
+</plist>`
+	got, err := parsePlist([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"CFBundleName": "Eclipse Temurin 17 & more",
+		"JavaVM": map[string]interface{}{
+			"JVMCapabilities":    []interface{}{"CommandLine", "JNI"},
+			"JVMPlatformVersion": 17.0,
+			"JVMBuild":           int64(9),
+			"JVMEnabled":         true,
+			"JVMDebug":           false,
+			"JVMReleaseDate":     time.Date(2023, 10, 17, 0, 0, 0, 0, time.UTC),
+			"JVMData":            []byte("Java"),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePlist() = %#v, want %#v", got, want)
+	}
+}
+
+func Test_parsePlistErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"binary", "bplist00\xd1\x01\x02"},
+		{"no plist", `<?xml version="1.0"?><dict></dict>`},
+		{"key missing", `<plist><dict><string>value</string></dict></plist>`},
+		{"truncated", `<plist><dict><key>CFBundleName</key>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parsePlist([]byte(tt.content)); err == nil {
+				t.Errorf("parsePlist() returned no error")
+			}
+		})
+	}
+}
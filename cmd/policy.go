@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var policyFile string
+var checkInputFile string
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "evaluate findings against a policy and exit with a non-zero exit code on violations",
+	Long: `Evaluates findings against a policy file and prints a table of all violations.
+
+The findings are read from a findings file written by 'scan --append-to-findings-json' (--input)
+or are detected by running the activated detection methods.
+
+The exit code is a combination of the following bits for all violations found:
+   2  vendor not allowed
+   4  version below the minimum version of its major version
+   8  license category banned
+  16  java binary found in a forbidden path`,
+	Run: func(cmd *cobra.Command, args []string) {
+		Check()
+	},
+}
+
+func init() {
+	addDetectionFlags(checkCmd.Flags())
+	checkCmd.Flags().StringVarP(&policyFile, "policy", "P", "", "policy file (yaml, json or toml) the findings are evaluated against")
+	checkCmd.Flags().StringVarP(&checkInputFile, "input", "i", "", "findings file to evaluate instead of running the detectors")
+
+	rootCmd.AddCommand(checkCmd)
+}
+
+// Policy describes which java installations are acceptable.
+// Empty lists do not restrict the findings.
+type Policy struct {
+	AllowedVendors          []string       `mapstructure:"allowed-vendors"`
+	MinimumVersions         map[string]int `mapstructure:"minimum-versions"`
+	BannedLicenseCategories []string       `mapstructure:"banned-license-categories"`
+	ForbiddenPaths          []string       `mapstructure:"forbidden-paths"`
+}
+
+type ViolationType int64
+
+const (
+	VendorNotAllowed ViolationType = iota
+	VersionTooOld
+	LicenseCategoryBanned
+	PathForbidden
+)
+
+func (v ViolationType) String() string {
+	switch v {
+	case VendorNotAllowed:
+		return "vendor-not-allowed"
+	case VersionTooOld:
+		return "version-too-old"
+	case LicenseCategoryBanned:
+		return "license-category-banned"
+	case PathForbidden:
+		return "path-forbidden"
+	}
+
+	return "unknown"
+}
+
+// ExitCode returns the bit of the exit code, that signals a violation of this type.
+func (v ViolationType) ExitCode() int {
+	return 2 << uint(v)
+}
+
+type Violation struct {
+	Type    ViolationType
+	Finding JavaInfo
+	Details string
+}
+
+func Check() {
+	policy, err := loadConfiguredPolicy()
+	if err != nil {
+		log.Fatalf("failed loading policy %s: %s", policyFile, err)
+	}
+	if policy == nil {
+		log.Fatalf("No policy configured! Use --policy or 'policy-rules' in the config file!")
+	}
+
+	var findings []JavaInfo
+	if checkInputFile != "" {
+		findings, err = readFindingsFile(checkInputFile)
+		if err != nil {
+			log.Fatalf("failed reading findings file %s: %s", checkInputFile, err)
+		}
+	} else {
+		if !isAnyDetectionMethodActivated() {
+			log.Fatalf("Neither a findings file nor detected methods configured! Use './java-scanner check --help' for a list of options!")
+		}
+		logActivatedDetectionMethods()
+		findings = runDetectors()
+	}
+
+	exitOnViolations(policy, findings)
+}
+
+// exitOnViolations prints the violations of the policy and exits with the combined exit code of their types.
+func exitOnViolations(policy *Policy, findings []JavaInfo) {
+	violations, err := evaluatePolicy(policy, findings)
+	if err != nil {
+		log.Fatalf("failed evaluating policy: %s", err)
+	}
+	if len(violations) == 0 {
+		log.Infof("No policy violations found in %d findings", len(findings))
+		return
+	}
+	printViolations(violations)
+	os.Exit(violationsExitCode(violations))
+}
+
+func loadPolicy(fileName string) (*Policy, error) {
+	policyConfig := viper.New()
+	policyConfig.SetConfigFile(fileName)
+	if err := policyConfig.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	policy := &Policy{}
+	if err := policyConfig.UnmarshalExact(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func readFindingsFile(fileName string) ([]JavaInfo, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var findings []JavaInfo
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var info JavaInfo
+		if err := json.Unmarshal(scanner.Bytes(), &info); err != nil {
+			return nil, err
+		}
+		findings = append(findings, info)
+	}
+	return findings, scanner.Err()
+}
+
+func evaluatePolicy(policy *Policy, findings []JavaInfo) ([]Violation, error) {
+	minimumVersions := map[int]int{}
+	for major, minimumBuild := range policy.MinimumVersions {
+		majorVersion, err := strconv.Atoi(major)
+		if err != nil {
+			return nil, fmt.Errorf("invalid major version '%s' in minimum-versions", major)
+		}
+		minimumVersions[majorVersion] = minimumBuild
+	}
+	var forbiddenPaths *regexp.Regexp
+	if len(policy.ForbiddenPaths) > 0 {
+		var err error
+		forbiddenPaths, err = regexp.Compile(strings.Join(policy.ForbiddenPaths, "|"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid forbidden-paths: %s", err)
+		}
+	}
+
+	var violations []Violation
+	for _, info := range findings {
+		if info.Component != "" {
+			// the runtime of a desktop component is evaluated as a java installation of its own
+			continue
+		}
+		if forbiddenPaths != nil && forbiddenPaths.MatchString(info.Exe) {
+			violations = append(violations, Violation{PathForbidden, info, "matches " + forbiddenPaths.FindString(info.Exe)})
+		}
+		if !info.Valid {
+			continue
+		}
+		if len(policy.AllowedVendors) > 0 && !containsFold(policy.AllowedVendors, info.Vendor) {
+			violations = append(violations, Violation{VendorNotAllowed, info, "vendor '" + info.Vendor + "'"})
+		}
+		if minimumBuild, found := minimumVersions[info.MajorVersion]; found && info.BuildNumber < minimumBuild {
+			violations = append(violations, Violation{VersionTooOld, info,
+				fmt.Sprintf("build %d below minimum %d", info.BuildNumber, minimumBuild)})
+		}
+		if containsFold(policy.BannedLicenseCategories, info.LicenseCategory.String()) {
+			violations = append(violations, Violation{LicenseCategoryBanned, info, "license category " + info.LicenseCategory.String()})
+		}
+	}
+	return violations, nil
+}
+
+func violationsExitCode(violations []Violation) int {
+	exitCode := 0
+	for _, violation := range violations {
+		exitCode |= violation.Type.ExitCode()
+	}
+	return exitCode
+}
+
+func printViolations(violations []Violation) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "VIOLATION\tHOSTNAME\tDETECTION METHOD\tEXE\tVENDOR\tMAJOR\tBUILD\tDETAILS")
+	for _, violation := range violations {
+		info := violation.Finding
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n", violation.Type, info.Hostname, info.DetectionMethod, info.Exe,
+			info.Vendor, info.MajorVersion, info.BuildNumber, violation.Details)
+	}
+	_ = writer.Flush()
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
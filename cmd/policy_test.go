@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func Test_evaluatePolicy(t *testing.T) {
+	policy := &Policy{
+		AllowedVendors:          []string{"Eclipse Adoptium", "Oracle Corporation"},
+		MinimumVersions:         map[string]int{"8": 392, "17": 9},
+		BannedLicenseCategories: []string{"oracle-otn"},
+		ForbiddenPaths:          []string{"^/opt/oracle/"},
+	}
+	tests := []struct {
+		name     string
+		info     JavaInfo
+		want     []ViolationType
+		exitCode int
+	}{
+		{"compliant", JavaInfo{Valid: true, Exe: "/usr/lib/jvm/temurin-17/bin/java", Vendor: "Eclipse Adoptium", MajorVersion: 17, BuildNumber: 9}, nil, 0},
+		{"vendor", JavaInfo{Valid: true, Exe: "/usr/lib/jvm/zulu-17/bin/java", Vendor: "Azul Systems, Inc.", MajorVersion: 17, BuildNumber: 9}, []ViolationType{VendorNotAllowed}, 2},
+		{"version", JavaInfo{Valid: true, Exe: "/usr/lib/jvm/temurin-8/bin/java", Vendor: "Eclipse Adoptium", MajorVersion: 8, BuildNumber: 382}, []ViolationType{VersionTooOld}, 4},
+		{"license and path", JavaInfo{Valid: true, Exe: "/opt/oracle/jdk-11/bin/java", Vendor: "Oracle Corporation", MajorVersion: 11, BuildNumber: 2, LicenseCategory: LicenseOracleOTN}, []ViolationType{PathForbidden, LicenseCategoryBanned}, 24},
+		{"invalid only path", JavaInfo{Valid: false, Exe: "/opt/oracle/jdk-11/bin/java"}, []ViolationType{PathForbidden}, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := evaluatePolicy(policy, []JavaInfo{tt.info})
+			if err != nil {
+				t.Fatalf("evaluatePolicy() error = %v", err)
+			}
+			var got []ViolationType
+			for _, violation := range violations {
+				got = append(got, violation.Type)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("evaluatePolicy() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("evaluatePolicy() = %v, want %v", got, tt.want)
+				}
+			}
+			if exitCode := violationsExitCode(violations); exitCode != tt.exitCode {
+				t.Errorf("violationsExitCode() = %v, want %v", exitCode, tt.exitCode)
+			}
+		})
+	}
+}
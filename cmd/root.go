@@ -17,10 +17,13 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -34,6 +37,11 @@ var rootCmd = &cobra.Command{
 	Use:   "java-scanner",
 	Short: "Java Scanner",
 	Long:  `Java Scanner is a programm to detect java installations and running java processes.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := applyConfiguration(cmd); err != nil {
+			log.Fatalf("invalid configuration: %s", err)
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		Scan()
 	},
@@ -79,33 +87,99 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.jps.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "named profile of scan options, e.g. server, workstation or container-host")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 
-	scanCmd.Flags().BoolVarP(&detectWindowsRegistry, "scan-windows-registry", "r", false, "Activate windows registry scanning")
-	scanCmd.Flags().BoolVarP(&detectLinuxAlternatives, "scan-linux-alternatives", "a", false, "Activate linux-alternatives scanning")
-	scanCmd.Flags().BoolVarP(&detectRunningProcesses, "scan-running-processes", "p", false, "Activate running processes scanning")
-	scanCmd.Flags().BoolVarP(&detectCurrentPath, "scan-current-path", "c", false, "Activate scanning of current path")
+	addDetectionFlags(scanCmd.Flags())
+	scanCmd.Flags().StringVarP(&outputDir, "output-dir", "o", ".", "directory the csv file and the findings file are written to")
+	scanCmd.Flags().BoolVarP(&appendToFindingsJson, "append-to-findings-json", "j", false, "append findings to findings.json file")
+	scanCmd.Flags().StringSliceVar(&csvPropertyColumns, "csv-property-columns", nil, "system properties added as extra columns to the csv file, e.g. java.home,sun.arch.data.model")
+	scanCmd.Flags().StringVar(&pushToUrl, "push-to", "", "push findings to the collector running at the given url (see 'java-scanner collect')")
+	scanCmd.Flags().StringVarP(&policyFile, "policy", "P", "", "evaluate findings against the policy file and exit with a non-zero exit code on violations (see 'java-scanner check')")
 
-	scanCmd.Flags().BoolVarP(&detectFileSystemScan, "scan-file-system", "f", false, "Activate running processes scanning")
+	rootCmd.AddCommand(scanCmd)
+}
+
+// addDetectionFlags adds the flags activating and configuring the detection methods.
+// They are shared by all commands running the detectors.
+func addDetectionFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&detectWindowsRegistry, "scan-windows-registry", "r", false, "Activate windows registry scanning")
+	flags.BoolVarP(&detectWindowsInstallations, "scan-windows-installations", "W", false,
+		"Activate scanning of the java products in the windows uninstall registry keys and the Program Files directories")
+	flags.BoolVarP(&detectMacOSJavaVirtualMachines, "scan-macos-jvms", "m", false,
+		"Activate scanning of the JDK bundles in /Library/Java/JavaVirtualMachines, ~/Library/Java/JavaVirtualMachines and of java_home on macOS")
+	flags.BoolVarP(&detectLinuxAlternatives, "scan-linux-alternatives", "a", false, "Activate linux-alternatives scanning")
+	flags.BoolVarP(&detectRunningProcesses, "scan-running-processes", "p", false, "Activate running processes scanning")
+	flags.BoolVarP(&detectCurrentPath, "scan-current-path", "c", false, "Activate scanning of current path")
+	flags.BoolVar(&detectCurrentPathAllUsers, "scan-current-path-all-users", false,
+		"Also resolve the java binary on the PATH of each local user with a login shell from their shell profiles")
+	flags.BoolVarP(&detectEnvironmentConfig, "scan-environment-config", "e", false,
+		"Activate scanning of JAVA_HOME, JRE_HOME, JDK_HOME and PATH in systemd units, profile scripts, shell rc files and setenv.sh")
+	flags.BoolVarP(&detectServices, "scan-services", "s", false,
+		"Activate scanning of systemd units, SysV init scripts and windows services launching a JVM")
+	flags.BoolVarP(&detectBuildConfig, "scan-build-config", "b", false,
+		"Activate scanning of maven toolchains, gradle.properties, .java-version, .sdkmanrc and .tool-versions files (requests are only matched to the java installations found by the other activated scans, e.g. -f)")
+	flags.StringSliceVar(&detectBuildConfigRootPaths, "scan-build-config-root-paths", []string{},
+		"A list of source and workspace root paths, where the build configuration scan has to start (the toolchains and gradle.properties of all users are always scanned)")
+	flags.BoolVarP(&detectCiTools, "scan-ci-tools", "t", false,
+		"Activate scanning of the JDK tools configured for jenkins, gitlab runners and teamcity agents")
+	flags.StringSliceVar(&detectCiToolsRootPaths, "scan-ci-tools-root-paths", []string{},
+		"A list of jenkins homes, jenkins agent root directories, gitlab runner configurations and teamcity agent directories scanned besides the default locations")
+	flags.BoolVarP(&detectDesktopComponents, "scan-desktop-components", "d", false,
+		"Activate scanning for Java Web Start, browser plugin, Java Update Scheduler and Java Control Panel settings")
+
+	flags.BoolVarP(&detectFileSystemScan, "scan-file-system", "f", false, "Activate running processes scanning")
 
 	defaultRootPaths := []string{"/usr/lib/jvm"}
-	scanCmd.Flags().StringSliceVarP(&detectFileSystemScanRootPaths,
+	flags.StringSliceVarP(&detectFileSystemScanRootPaths,
 		"scan-file-system-root-paths",
 		"R",
 		defaultRootPaths,
 		"A list of root paths, where the file system scan has to start")
 
 	defaultExcludePaths := []string{}
-	scanCmd.Flags().StringSliceVarP(&detectFileSystemScanExcludePaths,
+	flags.StringSliceVarP(&detectFileSystemScanExcludePaths,
 		"scan-file-system-exclude-paths",
 		"E",
 		defaultExcludePaths,
 		"A list of paths, that should be excluded from the search")
-	scanCmd.Flags().BoolVarP(&appendToFindingsJson, "append-to-findings-json", "j", false, "append findings to findings.json file")
-
-	rootCmd.AddCommand(scanCmd)
+	flags.StringSliceVar(&detectFileSystemScanExcludeGlobs,
+		"scan-file-system-exclude-globs",
+		[]string{},
+		"A list of gitignore-style glob patterns of files and directories, that should be excluded from the search")
+	flags.StringSliceVar(&detectFileSystemScanIncludeGlobs,
+		"scan-file-system-include-globs",
+		[]string{},
+		"A list of gitignore-style glob patterns, the search is restricted to")
+	flags.BoolVarP(&detectFileSystemScanOneFileSystem, "scan-file-system-one-file-system", "x", false,
+		"Do not descend into directories on other file systems than the root path, like 'find -xdev'")
+	flags.IntVar(&detectFileSystemScanMaxDepth, "scan-file-system-max-depth", 0,
+		"Maximum depth of directories below a root path, that are scanned (0 is unlimited)")
+	flags.StringSliceVar(&detectFileSystemScanSkipMountTypes, "scan-file-system-skip-mount-types", defaultSkipMountTypes,
+		"File system types of mounts, that are not scanned (read from "+mountInfoFile+")")
+	flags.BoolVar(&detectFileSystemScanFollowSymlinks, "scan-file-system-follow-symlinks", false,
+		"Follow symbolic links to directories, each directory is scanned once")
+	flags.BoolVar(&fingerprint, "fingerprint", false,
+		"Add SHA-256, size, owner and modification time of the java binary and the SHA-256 of lib/modules or rt.jar to the findings")
+	flags.StringVar(&knownBuildsFile, "known-builds", "",
+		"json file of known vendor builds and their SHA-256 to identify fingerprinted builds (implies --fingerprint)")
+	flags.BoolVar(&noCache, "no-cache", false,
+		"Analyze all java binaries found by the file system scan instead of reusing the cached results of unchanged ones")
+	flags.StringVar(&cacheFile, "cache-file", defaultCacheFile(), "file the analysis results of java binaries are cached in")
+	flags.BoolVar(&noExec, "no-exec", false,
+		"Never execute discovered java binaries, read the release file of their installation instead")
+	flags.BoolVar(&sudoRetry, "sudo", false,
+		"Retry java binaries, that failed to run, as root via 'sudo -n' and 'timeout'")
+	flags.DurationVar(&execTimeout, "exec-timeout", 30*time.Second,
+		"Time after which an executed java binary is killed (0 disables the timeout)")
+	flags.StringVar(&execUser, "exec-user", "",
+		"Unprivileged user java binaries are executed as, requires root privileges and disables the retry via --sudo")
+	flags.BoolVarP(&detectFileSystemScanArchives, "scan-file-system-archives", "A", false,
+		"Look for java installations inside zip, jar, war, ear, tar, tar.gz and tar.xz archives found by the file system scan")
+	flags.IntVar(&detectFileSystemScanArchivesMaxDepth, "scan-file-system-archives-max-depth", 2,
+		"Maximum nesting depth of archives, that are looked into")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -126,6 +200,8 @@ func initConfig() {
 		viper.SetConfigName(".jps")
 	}
 
+	viper.SetEnvPrefix("JPS")
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// maxNestedArchiveSize limits the size of nested archives, which have to be read into memory to look into them.
+const maxNestedArchiveSize = 512 * 1024 * 1024
+
+// maxReleaseFileSize limits the size of 'release' files, which are read completely.
+const maxReleaseFileSize = 64 * 1024
+
+// innerPathSeparator separates the path of an archive and the path of an entry within it.
+const innerPathSeparator = "!/"
+
+// errXzNotSupported is returned for tar.xz archives, as there is no xz decompression in the standard library
+// and archives are only read statically, without running external tools.
+var errXzNotSupported = errors.New("tar.xz archives are not supported")
+
+type archiveKind int
+
+const (
+	noArchive archiveKind = iota
+	zipArchive
+	tarArchive
+	tarGzArchive
+	tarXzArchive
+)
+
+func detectArchiveKind(fileName string) archiveKind {
+	name := strings.ToLower(fileName)
+	switch {
+	case strings.HasSuffix(name, ".zip"), strings.HasSuffix(name, ".jar"),
+		strings.HasSuffix(name, ".war"), strings.HasSuffix(name, ".ear"):
+		return zipArchive
+	case strings.HasSuffix(name, ".tar"):
+		return tarArchive
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return tarGzArchive
+	case strings.HasSuffix(name, ".tar.xz"), strings.HasSuffix(name, ".txz"):
+		return tarXzArchive
+	}
+	return noArchive
+}
+
+// archiveScanner collects java binaries and release files within an archive and its nested archives.
+type archiveScanner struct {
+	archivePath  string
+	maxDepth     int
+	javaBinaries []string
+	releaseFiles map[string]string
+	errors       []walkError
+}
+
+// scanArchive looks for java installations in the archive without extracting it.
+// It also returns the nested archives, that could not be read.
+func scanArchive(archivePath string, maxDepth int) ([]JavaInfo, []walkError, error) {
+	scanner := &archiveScanner{archivePath: archivePath, maxDepth: maxDepth, releaseFiles: map[string]string{}}
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = scanner.scan(detectArchiveKind(archivePath), file, stat.Size(), "", 1); err != nil {
+		return nil, nil, err
+	}
+
+	var result []JavaInfo
+	for _, innerPath := range scanner.javaBinaries {
+		info := JavaInfo{DetectionMethod: FileSystemArchive, Valid: true, AnalysisMethod: ReleaseFile}
+		info.Hostname, _ = os.Hostname()
+		info.Exe = archivePath + innerPathSeparator + innerPath
+		info.ArchivePath = archivePath
+		info.ArchiveInnerPath = innerPath
+		releaseFile, found := scanner.findReleaseFile(innerPath)
+		if found {
+			applyReleaseProperties(&info, parseReleaseFile(releaseFile))
+		} else {
+			addErrorText(&info, errors.New("no release file found for java binary in archive"), "")
+		}
+		result = append(result, info)
+	}
+	return result, scanner.errors, nil
+}
+
+func (scanner *archiveScanner) scan(kind archiveKind, reader io.ReaderAt, size int64, prefix string, depth int) error {
+	switch kind {
+	case zipArchive:
+		zipReader, err := zip.NewReader(reader, size)
+		if err != nil {
+			return err
+		}
+		for _, entry := range zipReader.File {
+			if entry.FileInfo().IsDir() {
+				continue
+			}
+			content, err := entry.Open()
+			if err != nil {
+				log.Warnf("Cannot read %s%s in archive %s: %s", prefix, entry.Name, scanner.archivePath, err)
+				continue
+			}
+			err = scanner.visit(prefix, entry.Name, int64(entry.UncompressedSize64), content, depth)
+			_ = content.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case tarArchive, tarGzArchive:
+		return scanner.scanTar(kind, io.NewSectionReader(reader, 0, size), prefix, depth)
+	case tarXzArchive:
+		return errXzNotSupported
+	}
+	return errors.New("unsupported archive type")
+}
+
+func (scanner *archiveScanner) scanTar(kind archiveKind, reader io.Reader, prefix string, depth int) error {
+	if kind == tarGzArchive {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeSymlink {
+			continue
+		}
+		if err = scanner.visit(prefix, header.Name, header.Size, tarReader, depth); err != nil {
+			return err
+		}
+	}
+}
+
+// visit records java binaries and release files and looks into nested archives up to the maximum depth.
+func (scanner *archiveScanner) visit(prefix string, name string, size int64, content io.Reader, depth int) error {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	innerPath := prefix + name
+	if isJavaBinaryName(path.Base(name)) && path.Base(path.Dir(name)) == "bin" {
+		scanner.javaBinaries = append(scanner.javaBinaries, innerPath)
+		return nil
+	}
+	if path.Base(name) == "release" && size <= maxReleaseFileSize {
+		releaseFile, err := io.ReadAll(io.LimitReader(content, maxReleaseFileSize))
+		if err != nil {
+			return err
+		}
+		scanner.releaseFiles[path.Dir(innerPath)] = string(releaseFile)
+		return nil
+	}
+
+	kind := detectArchiveKind(name)
+	if kind == noArchive || depth >= scanner.maxDepth {
+		return nil
+	}
+	if size > maxNestedArchiveSize {
+		log.Warnf("Skipping nested archive %s in %s, since it is larger than %d bytes", innerPath, scanner.archivePath, maxNestedArchiveSize)
+		return nil
+	}
+	nestedArchive, err := io.ReadAll(io.LimitReader(content, maxNestedArchiveSize))
+	if err != nil {
+		return err
+	}
+	err = scanner.scan(kind, bytes.NewReader(nestedArchive), int64(len(nestedArchive)), innerPath+innerPathSeparator, depth+1)
+	if err != nil {
+		log.Warnf("Cannot read nested archive %s in %s: %s", innerPath, scanner.archivePath, err)
+		scanner.errors = append(scanner.errors, walkError{Path: scanner.archivePath + innerPathSeparator + innerPath, Err: err})
+	}
+	return nil
+}
+
+// findReleaseFile returns the release file of the installation the java binary belongs to.
+// The java binary of the JRE within a JDK 8 uses the release file of the JDK.
+func (scanner *archiveScanner) findReleaseFile(javaBinaryInnerPath string) (string, bool) {
+	home := path.Dir(path.Dir(javaBinaryInnerPath))
+	if releaseFile, found := scanner.releaseFiles[home]; found {
+		return releaseFile, true
+	}
+	if path.Base(home) == "jre" {
+		releaseFile, found := scanner.releaseFiles[path.Dir(home)]
+		return releaseFile, found
+	}
+	return "", false
+}
+
+func isJavaBinaryName(fileName string) bool {
+	return strings.EqualFold(fileName, "java") || strings.EqualFold(fileName, "java.exe")
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const temurinReleaseFile = `IMPLEMENTOR="Eclipse Adoptium"
+IMPLEMENTOR_VERSION="Temurin-17.0.9+9"
+JAVA_VERSION="17.0.9"
+OS_ARCH="x86_64"
+`
+
+type archiveEntry struct {
+	name    string
+	content string
+}
+
+func zipFixture(t *testing.T, entries ...archiveEntry) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, entry := range entries {
+		file, err := writer.Create(entry.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func tarGzFixture(t *testing.T, entries ...archiveEntry) []byte {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	writer := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0755, Size: int64(len(entry.content)), Typeflag: tar.TypeReg}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func writeArchiveFixture(t *testing.T, name string, content []byte) string {
+	archivePath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(archivePath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return archivePath
+}
+
+func Test_scanArchive(t *testing.T) {
+	jdk := []archiveEntry{
+		{"jdk-17.0.9+9/release", temurinReleaseFile},
+		{"jdk-17.0.9+9/bin/java", "ELF"},
+		{"jdk-17.0.9+9/lib/modules", ""},
+	}
+	jdk8 := []archiveEntry{
+		{"jdk8u392-b08/release", "JAVA_VERSION=\"1.8.0_392\"\nIMPLEMENTOR=\"Temurin\"\n"},
+		{"jdk8u392-b08/bin/java", "ELF"},
+		{"jdk8u392-b08/jre/bin/java", "ELF"},
+	}
+	nested := zipFixture(t, archiveEntry{"dist/jdk.tar.gz", string(tarGzFixture(t, jdk...))})
+
+	tests := []struct {
+		name     string
+		fileName string
+		content  []byte
+		maxDepth int
+		want     map[string]int
+	}{
+		{"zip", "jdk.zip", zipFixture(t, jdk...), 1, map[string]int{"jdk-17.0.9+9/bin/java": 17}},
+		{"tar.gz", "jdk.tar.gz", tarGzFixture(t, jdk...), 1, map[string]int{"jdk-17.0.9+9/bin/java": 17}},
+		{"jre of jdk 8", "jdk8.tgz", tarGzFixture(t, jdk8...), 1, map[string]int{"jdk8u392-b08/bin/java": 8, "jdk8u392-b08/jre/bin/java": 8}},
+		{"nested", "bundle.zip", nested, 2, map[string]int{"dist/jdk.tar.gz!/jdk-17.0.9+9/bin/java": 17}},
+		{"nested beyond max depth", "bundle.zip", nested, 1, map[string]int{}},
+		{"no release file", "jre.zip", zipFixture(t, archiveEntry{"jre/bin/java.exe", "MZ"}), 1, map[string]int{"jre/bin/java.exe": 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := writeArchiveFixture(t, tt.fileName, tt.content)
+			findings, _, err := scanArchive(archivePath, tt.maxDepth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]int{}
+			for _, info := range findings {
+				got[info.ArchiveInnerPath] = info.MajorVersion
+				if info.Exe != archivePath+innerPathSeparator+info.ArchiveInnerPath || info.DetectionMethod != FileSystemArchive {
+					t.Errorf("finding %+v does not refer to its archive", info)
+				}
+				if info.MajorVersion == 0 && info.ErrorText == "" {
+					t.Errorf("finding %s without release file has no error text", info.ArchiveInnerPath)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("scanArchive() found %v, want %v", sortedKeys(got), sortedKeys(tt.want))
+			}
+			for innerPath, major := range tt.want {
+				if got[innerPath] != major {
+					t.Errorf("scanArchive() found %s with major version %d, want %d", innerPath, got[innerPath], major)
+				}
+			}
+		})
+	}
+}
+
+func Test_scanArchiveVendor(t *testing.T) {
+	archivePath := writeArchiveFixture(t, "jdk.zip", zipFixture(t,
+		archiveEntry{"jdk-17.0.9+9/bin/java", "ELF"}, archiveEntry{"jdk-17.0.9+9/release", temurinReleaseFile}))
+	findings, _, err := scanArchive(archivePath, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Vendor != "Eclipse Adoptium" || findings[0].VendorVersion != "Temurin-17.0.9+9" || findings[0].BuildNumber != 9 {
+		t.Errorf("scanArchive() = %+v, want the temurin 17.0.9 release properties", findings)
+	}
+}
+
+func Test_scanArchiveXz(t *testing.T) {
+	archivePath := writeArchiveFixture(t, "jdk.tar.xz", []byte("\xfd7zXZ\x00"))
+	if _, _, err := scanArchive(archivePath, 1); !errors.Is(err, errXzNotSupported) {
+		t.Errorf("scanArchive() of a tar.xz archive error = %v, want %v", err, errXzNotSupported)
+	}
+
+	// a nested tar.xz archive is reported, the rest of the archive is scanned
+	archivePath = writeArchiveFixture(t, "bundle.zip", zipFixture(t,
+		archiveEntry{"dist/jdk.tar.xz", "\xfd7zXZ\x00"}, archiveEntry{"jre/bin/java", "ELF"}))
+	findings, archiveErrors, err := scanArchive(archivePath, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].ArchiveInnerPath != "jre/bin/java" {
+		t.Errorf("scanArchive() = %+v, want the jre next to the nested tar.xz archive", findings)
+	}
+	if len(archiveErrors) != 1 || archiveErrors[0].Path != archivePath+"!/dist/jdk.tar.xz" || !errors.Is(archiveErrors[0].Err, errXzNotSupported) {
+		t.Errorf("scanArchive() errors = %+v, want the nested tar.xz archive", archiveErrors)
+	}
+}
+
+func Test_detectArchiveKind(t *testing.T) {
+	names := []string{"a.ZIP", "b.jar", "c.tar", "d.tar.gz", "e.tgz", "f.tar.xz", "g.txz", "h.rpm"}
+	want := []archiveKind{zipArchive, zipArchive, tarArchive, tarGzArchive, tarGzArchive, tarXzArchive, tarXzArchive, noArchive}
+	for i, name := range names {
+		if got := detectArchiveKind(name); got != want[i] {
+			t.Errorf("detectArchiveKind(%s) = %v, want %v", name, got, want[i])
+		}
+	}
+}
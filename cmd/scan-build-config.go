@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var detectBuildConfig bool
+var detectBuildConfigRootPaths []string
+
+// buildConfigExcludeGlobs are the directories of source trees, that do not contain build configurations of their own.
+var buildConfigExcludeGlobs = []string{".git/", ".svn/", "node_modules/", "target/", "build/", ".gradle/caches/"}
+
+// jdkRequest is a JDK requested by a build configuration, by version and vendor or by its home directory.
+type jdkRequest struct {
+	Source  string
+	Version string
+	Vendor  string
+	Home    string
+}
+
+func detectBuildConfigMain() []JavaInfo {
+	log.Infof("Starting detection '%s' below %s...", BuildConfig, detectBuildConfigRootPaths)
+	var result []JavaInfo
+	scanTimestamp := time.Now()
+	hostname, _ := os.Hostname()
+
+	var requests []jdkRequest
+	for _, home := range userHomeDirectories() {
+		requests = append(requests, readBuildConfigFile(filepath.Join(home, ".m2", "toolchains.xml"))...)
+		requests = append(requests, readBuildConfigFile(filepath.Join(home, ".gradle", "gradle.properties"))...)
+	}
+	walker, err := newFileSystemWalker(fileSystemWalkerOptions{ExcludeGlobs: buildConfigExcludeGlobs})
+	if err != nil {
+		log.Errorf("Not scanning build configurations: %s", err)
+		return result
+	}
+	for _, rootPath := range detectBuildConfigRootPaths {
+		walker.walk(rootPath, func(path string, entry fs.DirEntry) {
+			requests = append(requests, readBuildConfigFile(path)...)
+		})
+	}
+	for _, walkErr := range walker.errors {
+		notScannedPaths = append(notScannedPaths, notScannedPath{DetectionMethod: BuildConfig, Path: walkErr.Path, Reason: walkErr.Err.Error()})
+	}
+
+	analyzed := map[string]JavaInfo{}
+	for _, request := range requests {
+		info := JavaInfo{}
+		if request.Home != "" {
+			info = analyzeJavaBinaryOnce(analyzed, filepath.Join(request.Home, "bin", javaBinaryName()))
+		}
+		info.DetectionMethod = BuildConfig
+		info.ScanTimestamp = scanTimestamp
+		info.Hostname = hostname
+		info.ConfigSource = request.Source
+		info.RequestedVersion = request.Version
+		info.RequestedVendor = request.Vendor
+		if info.Valid {
+			info.MatchingRuntime = info.Exe
+		}
+		log.Infof("Build configuration %s requests JDK %s %s %s", request.Source, request.Vendor, request.Version, request.Home)
+		result = append(result, info)
+	}
+	log.Infof("number of JDKs requested by build configurations: %d!", len(result))
+	return result
+}
+
+// readBuildConfigFile returns the JDKs requested by a build configuration file, nothing if the file is none.
+func readBuildConfigFile(path string) []jdkRequest {
+	name := filepath.Base(path)
+	switch name {
+	case "toolchains.xml", "gradle.properties", ".java-version", ".sdkmanrc", ".tool-versions":
+	default:
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var requests []jdkRequest
+	switch name {
+	case "toolchains.xml":
+		requests, err = parseMavenToolchains(content)
+		if err != nil {
+			log.Warnf("Cannot parse maven toolchains %s: %s", path, err)
+		}
+	case "gradle.properties":
+		requests = parseGradleProperties(string(content))
+	case ".java-version":
+		// jenv, e.g. "17" or "temurin64-17.0.9"
+		if spec := strings.TrimSpace(string(content)); spec != "" {
+			requests = append(requests, parseJdkSpec(spec))
+		}
+	case ".sdkmanrc":
+		// e.g. "java=17.0.9-tem"
+		if spec := parseJavaProperties(string(content))["java"]; spec != "" {
+			requests = append(requests, parseJdkSpec(spec))
+		}
+	case ".tool-versions":
+		// asdf, e.g. "java temurin-17.0.9+9"
+		for _, line := range strings.Split(string(content), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 && fields[0] == "java" {
+				requests = append(requests, parseJdkSpec(fields[1]))
+			}
+		}
+	}
+	for i := range requests {
+		requests[i].Source = path
+	}
+	return requests
+}
+
+type mavenToolchains struct {
+	Toolchains []struct {
+		Type     string `xml:"type"`
+		Provides struct {
+			Version string `xml:"version"`
+			Vendor  string `xml:"vendor"`
+		} `xml:"provides"`
+		Configuration struct {
+			JdkHome string `xml:"jdkHome"`
+		} `xml:"configuration"`
+	} `xml:"toolchain"`
+}
+
+func parseMavenToolchains(content []byte) ([]jdkRequest, error) {
+	var toolchains mavenToolchains
+	if err := xml.Unmarshal(content, &toolchains); err != nil {
+		return nil, err
+	}
+	var requests []jdkRequest
+	for _, toolchain := range toolchains.Toolchains {
+		if toolchain.Type != "jdk" {
+			continue
+		}
+		requests = append(requests, jdkRequest{Version: strings.TrimSpace(toolchain.Provides.Version),
+			Vendor: strings.TrimSpace(toolchain.Provides.Vendor), Home: strings.TrimSpace(toolchain.Configuration.JdkHome)})
+	}
+	return requests, nil
+}
+
+// parseGradleProperties returns the JDKs of org.gradle.java.home and org.gradle.java.installations.paths.
+func parseGradleProperties(content string) []jdkRequest {
+	var requests []jdkRequest
+	properties := parseJavaProperties(content)
+	if javaHome := properties["org.gradle.java.home"]; javaHome != "" {
+		requests = append(requests, jdkRequest{Home: javaHome})
+	}
+	for _, installation := range strings.Split(properties["org.gradle.java.installations.paths"], ",") {
+		if installation = strings.TrimSpace(installation); installation != "" {
+			requests = append(requests, jdkRequest{Home: installation})
+		}
+	}
+	return requests
+}
+
+// parseJdkSpec splits a JDK identifier of jenv, SDKMAN! or asdf into version and vendor,
+// e.g. "17.0.9-tem", "temurin-17.0.9+9" or "1.8".
+func parseJdkSpec(spec string) jdkRequest {
+	var request jdkRequest
+	var vendor []string
+	for _, part := range strings.Split(spec, "-") {
+		if request.Version == "" && part != "" && unicode.IsDigit(rune(part[0])) {
+			request.Version = part
+		} else if request.Version == "" || len(vendor) == 0 {
+			vendor = append(vendor, part)
+		}
+	}
+	request.Vendor = strings.Join(vendor, "-")
+	return request
+}
+
+// requestedDistributions maps the vendor names of toolchains, SDKMAN! and asdf to the distribution.
+var requestedDistributions = map[string]Distribution{
+	"oracle": DistributionOracle, "temurin": DistributionTemurin, "tem": DistributionTemurin, "adoptium": DistributionTemurin,
+	"adoptopenjdk": DistributionTemurin, "eclipse": DistributionTemurin, "corretto": DistributionCorretto, "amzn": DistributionCorretto,
+	"amazon": DistributionCorretto, "zulu": DistributionZulu, "azul": DistributionZulu, "liberica": DistributionLiberica,
+	"librca": DistributionLiberica, "bellsoft": DistributionLiberica, "semeru": DistributionSemeru, "sem": DistributionSemeru,
+	"ibm": DistributionSemeru, "redhat": DistributionRedHat, "microsoft": DistributionMicrosoft, "ms": DistributionMicrosoft,
+	"sapmachine": DistributionSapMachine, "sapmchn": DistributionSapMachine, "sap": DistributionSapMachine,
+	"jetbrains": DistributionJBR, "jbr": DistributionJBR, "graalce": DistributionGraalVMCE,
+	"graalvm-community": DistributionGraalVMCE, "graal": DistributionGraalVMEE, "graalvm": DistributionGraalVMEE,
+}
+
+// requestedDistribution returns the distribution of a requested vendor, unknown for any vendor like "openjdk".
+func requestedDistribution(vendor string) Distribution {
+	vendor = strings.ToLower(strings.TrimRight(vendor, "0123456789"))
+	if distribution, found := requestedDistributions[vendor]; found {
+		return distribution
+	}
+	return DistributionUnknown
+}
+
+// correlateRequestedJdks sets the matching runtime of each finding, that requests a JDK by version and vendor,
+// to a java installation found by the other detection methods.
+// Requests naming a JDK home, that has been analyzed successfully, already match their own home.
+func correlateRequestedJdks(findings []JavaInfo) {
+	for i := range findings {
+		request := &findings[i]
+		if (request.RequestedVersion == "" && request.RequestedVendor == "") || request.MatchingRuntime != "" {
+			continue
+		}
+		major, _ := extractMajorAndBuildNumber(request.RequestedVersion)
+		distribution := requestedDistribution(request.RequestedVendor)
+		for _, installation := range findings {
+			if !isJavaInstallation(installation) || installation.RequestedVersion != "" || installation.RequestedVendor != "" {
+				continue
+			}
+			if (major == 0 || installation.MajorVersion == major) && (distribution == DistributionUnknown || installation.Distribution == distribution) {
+				request.MatchingRuntime = installation.Exe
+				break
+			}
+		}
+	}
+}
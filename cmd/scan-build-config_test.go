@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseJdkSpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		want jdkRequest
+	}{
+		{"17", jdkRequest{Version: "17"}},
+		{"17.0.9-tem", jdkRequest{Version: "17.0.9", Vendor: "tem"}},
+		{"temurin-17.0.9+9", jdkRequest{Version: "17.0.9+9", Vendor: "temurin"}},
+		{"temurin64-17.0.9", jdkRequest{Version: "17.0.9", Vendor: "temurin64"}},
+		{"graalvm-community-21.0.1", jdkRequest{Version: "21.0.1", Vendor: "graalvm-community"}},
+		{"openjdk-21-ea", jdkRequest{Version: "21", Vendor: "openjdk"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			if got := parseJdkSpec(tt.spec); got != tt.want {
+				t.Errorf("parseJdkSpec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseMavenToolchains(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<toolchains>
+  <toolchain>
+    <type>jdk</type>
+    <provides>
+      <version>17</version>
+      <vendor>temurin</vendor>
+    </provides>
+    <configuration>
+      <jdkHome>/opt/jdk-17</jdkHome>
+    </configuration>
+  </toolchain>
+  <toolchain>
+    <type>protobuf</type>
+    <provides><version>3.21</version></provides>
+  </toolchain>
+</toolchains>`
+	got, err := parseMavenToolchains([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []jdkRequest{{Version: "17", Vendor: "temurin", Home: "/opt/jdk-17"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMavenToolchains() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_correlateRequestedJdks(t *testing.T) {
+	findings := []JavaInfo{
+		{Exe: "/opt/zulu-17/bin/java", Valid: true, MajorVersion: 17, Distribution: DistributionZulu},
+		{Exe: "/opt/temurin-17/bin/java", Valid: true, MajorVersion: 17, Distribution: DistributionTemurin},
+		{DetectionMethod: BuildConfig, RequestedVersion: "17.0.9", RequestedVendor: "tem"},
+		{DetectionMethod: BuildConfig, RequestedVersion: "17", RequestedVendor: "openjdk"},
+		{DetectionMethod: BuildConfig, RequestedVersion: "21", RequestedVendor: "temurin"},
+		// a toolchain naming its JDK home matches its own home
+		{DetectionMethod: BuildConfig, Exe: "/opt/jdk-17/bin/java", Valid: true, MajorVersion: 17, RequestedVersion: "17",
+			MatchingRuntime: "/opt/jdk-17/bin/java"},
+	}
+	correlateRequestedJdks(findings)
+	want := []string{"", "", "/opt/temurin-17/bin/java", "/opt/zulu-17/bin/java", "", "/opt/jdk-17/bin/java"}
+	for i, finding := range findings {
+		if finding.MatchingRuntime != want[i] {
+			t.Errorf("finding %d: MatchingRuntime = %v, want %v", i, finding.MatchingRuntime, want[i])
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+var detectCiTools bool
+var detectCiToolsRootPaths []string
+
+// jenkinsHomes are the default home directories of jenkins controllers, besides $JENKINS_HOME and ~/.jenkins of each user.
+var jenkinsHomes = []string{"/var/lib/jenkins", "/var/jenkins_home", `C:\ProgramData\Jenkins\.jenkins`, `C:\Program Files\Jenkins`}
+
+// gitlabRunnerConfigs are the default configuration files of gitlab runners, besides ~/.gitlab-runner/config.toml of each user.
+var gitlabRunnerConfigs = []string{"/etc/gitlab-runner/config.toml", `C:\GitLab-Runner\config.toml`}
+
+// teamcityAgentHomes are the default installation directories of teamcity build agents.
+var teamcityAgentHomes = []string{"/opt/buildAgent", "/opt/TeamCity/buildAgent", "/home/*/buildAgent", `C:\BuildAgent`}
+
+// jenkinsJdkInstallerVendors are the vendors of the JDKs the jenkins installers download.
+var jenkinsJdkInstallerVendors = map[string]string{"JDKInstaller": "oracle", "AdoptOpenJDKInstaller": "temurin"}
+
+// ciTool is a JDK tool configured on a CI server or agent.
+type ciTool struct {
+	Source        string
+	Name          string
+	Home          string
+	InstallSource string
+	Version       string
+	Vendor        string
+}
+
+func detectCiToolsMain() []JavaInfo {
+	log.Infof("Starting detection '%s'...", CiTools)
+	var result []JavaInfo
+	scanTimestamp := time.Now()
+	hostname, _ := os.Hostname()
+
+	var tools []ciTool
+	for _, home := range ciToolHomes(jenkinsHomes, "JENKINS_HOME", ".jenkins") {
+		tools = append(tools, findJenkinsJdkTools(home)...)
+	}
+	for _, config := range ciToolHomes(gitlabRunnerConfigs, "", filepath.Join(".gitlab-runner", "config.toml")) {
+		tools = append(tools, findGitlabRunnerJdkTools(config)...)
+	}
+	for _, home := range ciToolHomes(teamcityAgentHomes, "", "") {
+		tools = append(tools, findTeamcityAgentJdkTools(home)...)
+	}
+
+	analyzed := map[string]JavaInfo{}
+	for _, tool := range tools {
+		info := JavaInfo{}
+		if tool.Home != "" {
+			if javaBinary := javaBinaryOfToolHome(tool.Home); javaBinary != "" {
+				info = analyzeJavaBinaryOnce(analyzed, javaBinary)
+			}
+		}
+		info.DetectionMethod = CiTools
+		info.ScanTimestamp = scanTimestamp
+		info.Hostname = hostname
+		info.ConfigSource = tool.Source
+		info.ToolName = tool.Name
+		info.InstallSource = tool.InstallSource
+		if !info.Valid {
+			// installed on agents only, the installer tells which JDK the builds need
+			info.RequestedVersion = tool.Version
+			info.RequestedVendor = tool.Vendor
+		}
+		log.Infof("%s configures JDK tool %s at %s installed from %s", tool.Source, tool.Name, tool.Home, tool.InstallSource)
+		result = append(result, info)
+	}
+	log.Infof("number of JDK tools configured on CI servers and agents: %d!", len(result))
+	return result
+}
+
+// ciToolHomes returns the existing default paths, the path of the environment variable, the path relative to
+// each user's home directory and the root paths given by the user.
+func ciToolHomes(defaults []string, variable string, userPath string) []string {
+	patterns := append([]string{}, defaults...)
+	if variable != "" && os.Getenv(variable) != "" {
+		patterns = append(patterns, os.Getenv(variable))
+	}
+	if userPath != "" {
+		for _, home := range userHomeDirectories() {
+			patterns = append(patterns, filepath.Join(home, userPath))
+		}
+	}
+	var paths []string
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		paths = append(paths, matches...)
+	}
+	for _, rootPath := range detectCiToolsRootPaths {
+		if _, err := os.Stat(rootPath); err == nil {
+			paths = append(paths, rootPath)
+		}
+	}
+	return uniqueStrings(paths)
+}
+
+type jenkinsJdkDescriptor struct {
+	Installations []struct {
+		Name       string `xml:"name"`
+		Home       string `xml:"home"`
+		Installers struct {
+			Installers []jenkinsInstaller `xml:",any"`
+		} `xml:"properties>hudson.tools.InstallSourceProperty>installers"`
+	} `xml:"installations>jdk"`
+}
+
+type jenkinsInstaller struct {
+	XMLName xml.Name
+	ID      string `xml:"id"`
+	URL     string `xml:"url"`
+	Command string `xml:"command"`
+}
+
+// findJenkinsJdkTools returns the JDK tools of hudson.model.JDK.xml and the JDKs installed automatically below
+// tools/hudson.model.JDK, also found on agents, that have no hudson.model.JDK.xml.
+func findJenkinsJdkTools(home string) []ciTool {
+	var tools []ciTool
+	toolsDirectory := filepath.Join(home, "tools", "hudson.model.JDK")
+	configured := map[string]bool{}
+	source := filepath.Join(home, "hudson.model.JDK.xml")
+	if content, err := os.ReadFile(source); err == nil {
+		parsed, err := parseJenkinsJdkTools(content)
+		if err != nil {
+			log.Warnf("Cannot parse jenkins JDK tools %s: %s", source, err)
+		}
+		for _, tool := range parsed {
+			tool.Source = source
+			if tool.Home == "" {
+				tool.Home = filepath.Join(toolsDirectory, tool.Name)
+			}
+			configured[filepath.Join(toolsDirectory, tool.Name)] = true
+			tools = append(tools, tool)
+		}
+	}
+	directories, _ := filepath.Glob(filepath.Join(toolsDirectory, "*"))
+	for _, directory := range directories {
+		if info, err := os.Stat(directory); err != nil || !info.IsDir() || configured[directory] {
+			continue
+		}
+		tool := ciTool{Source: toolsDirectory, Name: filepath.Base(directory), Home: directory}
+		if installedFrom, err := os.ReadFile(filepath.Join(directory, ".installedFrom")); err == nil {
+			tool.InstallSource = strings.TrimSpace(string(installedFrom))
+		}
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+func parseJenkinsJdkTools(content []byte) ([]ciTool, error) {
+	var descriptor jenkinsJdkDescriptor
+	// jenkins writes XML 1.1 declarations, the content is XML 1.0 compatible
+	content = []byte(strings.Replace(string(content), "version='1.1'", "version='1.0'", 1))
+	if err := xml.Unmarshal(content, &descriptor); err != nil {
+		return nil, err
+	}
+	var tools []ciTool
+	for _, installation := range descriptor.Installations {
+		tool := ciTool{Name: strings.TrimSpace(installation.Name), Home: strings.TrimSpace(installation.Home)}
+		var sources []string
+		for _, installer := range installation.Installers.Installers {
+			// e.g. hudson.tools.JDKInstaller or io.jenkins.plugins.adoptopenjdk.AdoptOpenJDKInstaller
+			kind := installer.XMLName.Local[strings.LastIndex(installer.XMLName.Local, ".")+1:]
+			origin := strings.TrimSpace(installer.ID + installer.URL + installer.Command)
+			sources = append(sources, strings.TrimSpace(kind+" "+origin))
+			if vendor, found := jenkinsJdkInstallerVendors[kind]; found && tool.Version == "" {
+				tool.Vendor = vendor
+				tool.Version = jenkinsInstallerVersion(installer.ID)
+			}
+		}
+		tool.InstallSource = strings.Join(sources, ";")
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// jenkinsInstallerVersion returns the version of a JDK installer id, e.g. 8u202 of jdk-8u202-oth-JPR or 17.0.9+9 of jdk-17.0.9+9.
+func jenkinsInstallerVersion(id string) string {
+	version, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(id, "jdk"), "-"), "-")
+	return version
+}
+
+type gitlabRunnerConfig struct {
+	Runners []struct {
+		Name        string   `toml:"name"`
+		Executor    string   `toml:"executor"`
+		Environment []string `toml:"environment"`
+	} `toml:"runners"`
+}
+
+func findGitlabRunnerJdkTools(config string) []ciTool {
+	if info, err := os.Stat(config); err == nil && info.IsDir() {
+		config = filepath.Join(config, "config.toml")
+	}
+	content, err := os.ReadFile(config)
+	if err != nil {
+		return nil
+	}
+	tools, err := parseGitlabRunnerJdkTools(content)
+	if err != nil {
+		log.Warnf("Cannot parse gitlab runner configuration %s: %s", config, err)
+	}
+	for i := range tools {
+		tools[i].Source = config
+	}
+	return tools
+}
+
+// parseGitlabRunnerJdkTools returns the java homes set by the environment of the runners.
+func parseGitlabRunnerJdkTools(content []byte) ([]ciTool, error) {
+	var config gitlabRunnerConfig
+	if err := toml.Unmarshal(content, &config); err != nil {
+		return nil, err
+	}
+	var tools []ciTool
+	for _, runner := range config.Runners {
+		for _, variable := range runner.Environment {
+			name, value, _ := strings.Cut(variable, "=")
+			if containsFold(javaHomeVariables, name) && value != "" {
+				tools = append(tools, ciTool{Name: runner.Name + ":" + name, Home: value})
+			}
+		}
+	}
+	return tools, nil
+}
+
+// findTeamcityAgentJdkTools returns the JDKs of the env.JAVA_HOME and env.JDK_* parameters of the agent
+// and the JVM its launcher runs the agent with.
+func findTeamcityAgentJdkTools(home string) []ciTool {
+	var tools []ciTool
+	source := filepath.Join(home, "conf", "buildAgent.properties")
+	if content, err := os.ReadFile(source); err == nil {
+		properties := parseJavaProperties(string(content))
+		for _, name := range sortedKeys(properties) {
+			if isTeamcityJdkParameter(name) && properties[name] != "" {
+				tools = append(tools, ciTool{Source: source, Name: strings.TrimPrefix(name, "env."), Home: properties[name]})
+			}
+		}
+	}
+	wrapperConf := filepath.Join(home, "launcher", "conf", "wrapper.conf")
+	if content, err := os.ReadFile(wrapperConf); err == nil {
+		// the wrapper takes backslashes literally, unlike java properties files
+		javaCommand := parseReleaseFile(string(content))["wrapper.java.command"]
+		if filepath.IsAbs(javaCommand) {
+			tools = append(tools, ciTool{Source: wrapperConf, Name: "wrapper.java.command", Home: filepath.Dir(filepath.Dir(javaCommand))})
+		}
+	}
+	return tools
+}
+
+// isTeamcityJdkParameter checks for the parameters teamcity agents report their JDKs with, e.g. env.JDK_17_0_x64.
+func isTeamcityJdkParameter(name string) bool {
+	variable := strings.TrimPrefix(name, "env.")
+	if variable == name {
+		return false
+	}
+	return containsFold(javaHomeVariables, variable) || strings.HasPrefix(variable, "JDK_") || strings.HasPrefix(variable, "JRE_")
+}
+
+// javaBinaryOfToolHome returns the java binary of a tool home, which is the JDK or, for extracted archives, contains it.
+func javaBinaryOfToolHome(home string) string {
+	candidates := []string{filepath.Join(home, "bin", javaBinaryName())}
+	nested, _ := filepath.Glob(filepath.Join(home, "*", "bin", javaBinaryName()))
+	if runtime.GOOS == "darwin" {
+		bundles, _ := filepath.Glob(filepath.Join(home, "*", "Contents", "Home", "bin", javaBinaryName()))
+		nested = append(nested, bundles...)
+	}
+	for _, candidate := range append(candidates, nested...) {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// correlateCiTools sets the matching runtime of each JDK tool to the java installation of the other detection methods,
+// that is the same binary, e.g. /usr/lib/jvm/java-17 configured in jenkins and /usr/lib/jvm/java-17-openjdk-amd64 found.
+func correlateCiTools(findings []JavaInfo) {
+	installations := map[string]string{}
+	for _, installation := range findings {
+		if !isJavaInstallation(installation) || installation.DetectionMethod == CiTools || installation.DetectionMethod == BuildConfig {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(installation.Exe); err == nil {
+			if _, found := installations[resolved]; !found {
+				installations[resolved] = installation.Exe
+			}
+		}
+	}
+	for i := range findings {
+		tool := &findings[i]
+		if tool.DetectionMethod != CiTools || !tool.Valid {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(tool.Exe); err == nil {
+			tool.MatchingRuntime = installations[resolved]
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func Test_parseJenkinsJdkTools(t *testing.T) {
+	content := `<?xml version='1.1' encoding='UTF-8'?>
+<hudson.model.JDK_-DescriptorImpl plugin="jdk-tool@73.vddf737284550">
+  <installations>
+    <jdk>
+      <name>jdk8</name>
+      <home></home>
+      <properties>
+        <hudson.tools.InstallSourceProperty>
+          <installers>
+            <hudson.tools.JDKInstaller>
+              <id>jdk-8u202-oth-JPR</id>
+              <acceptLicense>true</acceptLicense>
+            </hudson.tools.JDKInstaller>
+          </installers>
+        </hudson.tools.InstallSourceProperty>
+      </properties>
+    </jdk>
+    <jdk>
+      <name>temurin-17</name>
+      <home>/usr/lib/jvm/temurin-17-jdk-amd64</home>
+      <properties/>
+    </jdk>
+    <jdk>
+      <name>zulu-21</name>
+      <home></home>
+      <properties>
+        <hudson.tools.InstallSourceProperty>
+          <installers>
+            <hudson.tools.ZipExtractionInstaller>
+              <url>https://cdn.azul.com/zulu/bin/zulu21.30.15-ca-jdk21.0.1-linux_x64.tar.gz</url>
+              <subdir>zulu21.30.15-ca-jdk21.0.1-linux_x64</subdir>
+            </hudson.tools.ZipExtractionInstaller>
+          </installers>
+        </hudson.tools.InstallSourceProperty>
+      </properties>
+    </jdk>
+  </installations>
+</hudson.model.JDK_-DescriptorImpl>`
+	got, err := parseJenkinsJdkTools([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ciTool{
+		{Name: "jdk8", InstallSource: "JDKInstaller jdk-8u202-oth-JPR", Version: "8u202", Vendor: "oracle"},
+		{Name: "temurin-17", Home: "/usr/lib/jvm/temurin-17-jdk-amd64"},
+		{Name: "zulu-21", InstallSource: "ZipExtractionInstaller https://cdn.azul.com/zulu/bin/zulu21.30.15-ca-jdk21.0.1-linux_x64.tar.gz"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseJenkinsJdkTools() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_parseGitlabRunnerJdkTools(t *testing.T) {
+	content := `concurrent = 4
+
+[[runners]]
+  name = "build-1"
+  url = "https://gitlab.example.com/"
+  executor = "shell"
+  environment = ["JAVA_HOME=/opt/jdk-17", "MAVEN_OPTS=-Xmx1g"]
+
+[[runners]]
+  name = "docker-1"
+  executor = "docker"
+  [runners.docker]
+    image = "eclipse-temurin:21"
+`
+	got, err := parseGitlabRunnerJdkTools([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ciTool{{Name: "build-1:JAVA_HOME", Home: "/opt/jdk-17"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGitlabRunnerJdkTools() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_isTeamcityJdkParameter(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"env.JAVA_HOME", true},
+		{"env.JDK_17_0_x64", true},
+		{"env.JRE_1_8", true},
+		{"env.MAVEN_HOME", false},
+		{"JDK_17", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTeamcityJdkParameter(tt.name); got != tt.want {
+				t.Errorf("isTeamcityJdkParameter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findTeamcityAgentJdkTools(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture uses posix paths")
+	}
+	home := t.TempDir()
+	writeFixtureFiles(t, home, map[string]string{
+		"conf/buildAgent.properties": "serverUrl=http\\://teamcity.example.com\nenv.JDK_17_0_x64=/opt/jdk-17\nenv.MAVEN_HOME=/opt/maven\n" +
+			"env.JAVA_HOME=/opt/jdk-11\n",
+		"launcher/conf/wrapper.conf": "# TeamCity agent launcher\nwrapper.java.command=/opt/jdk-21/bin/java\nwrapper.java.additional.1=-Xmx512m\n",
+	})
+	properties := filepath.Join(home, "conf", "buildAgent.properties")
+	want := []ciTool{
+		{Source: properties, Name: "JAVA_HOME", Home: "/opt/jdk-11"},
+		{Source: properties, Name: "JDK_17_0_x64", Home: "/opt/jdk-17"},
+		{Source: filepath.Join(home, "launcher", "conf", "wrapper.conf"), Name: "wrapper.java.command", Home: "/opt/jdk-21"},
+	}
+	if got := findTeamcityAgentJdkTools(home); !reflect.DeepEqual(got, want) {
+		t.Errorf("findTeamcityAgentJdkTools() = %+v, want %+v", got, want)
+	}
+
+	// the wrapper takes backslashes literally
+	windowsWrapperConf := "wrapper.java.command=C:\\Program Files\\Eclipse Adoptium\\jdk-17\\bin\\java.exe\r\n"
+	if got := parseReleaseFile(windowsWrapperConf)["wrapper.java.command"]; got != `C:\Program Files\Eclipse Adoptium\jdk-17\bin\java.exe` {
+		t.Errorf("parseReleaseFile() = %v, want the windows path", got)
+	}
+}
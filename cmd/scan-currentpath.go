@@ -9,6 +9,11 @@ import (
 func detectCurrentPathMain() []JavaInfo {
 	log.Infof("Starting detection '%s'...", CurrentPath)
 	var result []JavaInfo
+	template := JavaInfo{ScanTimestamp: time.Now(), DetectionMethod: CurrentPath}
+	template.Hostname, _ = os.Hostname()
+	if detectCurrentPathAllUsers {
+		result = append(result, detectCurrentPathOfUsers(template)...)
+	}
 
 	path, err := exec.LookPath("java")
 	if err != nil {
@@ -16,8 +21,7 @@ func detectCurrentPathMain() []JavaInfo {
 		return result
 	}
 
-	info := JavaInfo{ScanTimestamp: time.Now(), DetectionMethod: CurrentPath}
-	info.Hostname, _ = os.Hostname()
+	info := template
 	info.Exe = path
 	analyzeJavaBinaryMain(&info)
 	result = append(result, info)
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+var detectDesktopComponents bool
+
+// desktopComponentFiles are the files of the Java Web Start and browser plugin components,
+// relative to the home directory of the runtime they belong to.
+var desktopComponentFiles = map[string][]string{
+	"java-web-start": {"bin/javaws", "bin/javaws.exe", "jre/bin/javaws", "jre/bin/javaws.exe"},
+	"browser-plugin": {
+		"lib/amd64/libnpjp2.so", "lib/i386/libnpjp2.so", "lib/libnpjp2.so",
+		"jre/lib/amd64/libnpjp2.so", "jre/lib/i386/libnpjp2.so",
+		"bin/plugin2/npjp2.dll", "jre/bin/plugin2/npjp2.dll",
+		"lib/libnpjp2.dylib",
+	},
+}
+
+// detectDesktopComponentsMain looks for the desktop components of the runtimes found by the other detection methods,
+// for the Java Update Scheduler and for the Java Control Panel settings of all users.
+func detectDesktopComponentsMain(runtimes []JavaInfo) []JavaInfo {
+	log.Infof("Starting detection '%s'...", DesktopComponents)
+	result := findDesktopComponents(runtimes, updateSchedulerFiles(), deploymentPropertiesFiles(userHomeDirectories()))
+	log.Infof("number of detected desktop components: %d!", len(result))
+	return result
+}
+
+func findDesktopComponents(runtimes []JavaInfo, updateSchedulers []string, deploymentPropertiesFiles []userFile) []JavaInfo {
+	var result []JavaInfo
+	scanTimestamp := time.Now()
+	hostname, _ := os.Hostname()
+
+	checked := map[string]bool{}
+	for _, runtimeInfo := range runtimes {
+		if runtimeInfo.Exe == "" || runtimeInfo.ArchivePath != "" || !isJavaInstallation(runtimeInfo) {
+			continue
+		}
+		javaHome := filepath.Dir(filepath.Dir(runtimeInfo.Exe))
+		if checked[javaHome] {
+			continue
+		}
+		checked[javaHome] = true
+		for _, component := range sortedKeys(desktopComponentFiles) {
+			for _, file := range desktopComponentFiles[component] {
+				componentPath := filepath.Join(javaHome, filepath.FromSlash(file))
+				if _, err := os.Stat(componentPath); err != nil {
+					continue
+				}
+				// the component is reported with the information about the runtime it belongs to
+				info := runtimeInfo
+				info.DetectionMethod = DesktopComponents
+				info.ScanTimestamp = scanTimestamp
+				info.Component = component
+				info.ComponentPath = componentPath
+				log.Infof("Found %s %s of java installation %s", component, componentPath, javaHome)
+				result = append(result, info)
+				break
+			}
+		}
+	}
+
+	for _, updateScheduler := range updateSchedulers {
+		if _, err := os.Stat(updateScheduler); err == nil {
+			log.Infof("Found Java Update Scheduler %s", updateScheduler)
+			result = append(result, JavaInfo{DetectionMethod: DesktopComponents, ScanTimestamp: scanTimestamp, Hostname: hostname,
+				Component: "update-scheduler", ComponentPath: updateScheduler})
+		}
+	}
+
+	for _, deploymentProperties := range deploymentPropertiesFiles {
+		content, err := os.ReadFile(deploymentProperties.Path)
+		if err != nil {
+			continue
+		}
+		log.Infof("Found Java Control Panel settings %s", deploymentProperties.Path)
+		result = append(result, JavaInfo{DetectionMethod: DesktopComponents, ScanTimestamp: scanTimestamp, Hostname: hostname,
+			Username: deploymentProperties.Username, Component: "deployment-properties", ComponentPath: deploymentProperties.Path,
+			Settings: parseJavaProperties(string(content))})
+	}
+	return result
+}
+
+// isJavaInstallation checks for a valid java installation. Desktop components are reported with the information
+// about their runtime, but are no java installations of their own, that would be counted twice.
+func isJavaInstallation(info JavaInfo) bool {
+	return info.Valid && info.Component == ""
+}
+
+func updateSchedulerFiles() []string {
+	var files []string
+	for _, variable := range []string{"CommonProgramFiles(x86)", "CommonProgramFiles"} {
+		if commonProgramFiles := os.Getenv(variable); commonProgramFiles != "" {
+			files = append(files, filepath.Join(commonProgramFiles, "Java", "Java Update", "jusched.exe"))
+		}
+	}
+	return files
+}
+
+type userFile struct {
+	Path     string
+	Username string
+}
+
+// deploymentPropertiesFiles returns the system wide and the per user deployment.properties files of the Java Control Panel.
+func deploymentPropertiesFiles(homes []string) []userFile {
+	var files []userFile
+	var userDirectory string
+	switch runtime.GOOS {
+	case "windows":
+		files = append(files, userFile{Path: filepath.Join(os.Getenv("WINDIR"), "Sun", "Java", "Deployment", "deployment.properties")})
+		userDirectory = filepath.Join("AppData", "LocalLow", "Sun", "Java", "Deployment")
+	case "darwin":
+		files = append(files, userFile{Path: "/Library/Application Support/Oracle/Java/Deployment/deployment.properties"})
+		userDirectory = "Library/Application Support/Oracle/Java/Deployment"
+	default:
+		files = append(files, userFile{Path: "/etc/.java/deployment/deployment.properties"})
+		userDirectory = ".java/deployment"
+	}
+	for _, home := range homes {
+		files = append(files, userFile{Path: filepath.Join(home, userDirectory, "deployment.properties"), Username: filepath.Base(home)})
+	}
+	return files
+}
+
+// parseJavaProperties parses the key=value or key: value lines of a java properties file and unescapes backslash escapes.
+// Lines ending with a backslash continue on the next line.
+func parseJavaProperties(content string) map[string]string {
+	properties := map[string]string{}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t")
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		for strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, `\`) + strings.TrimLeft(lines[i], " \t")
+		}
+		separator := strings.IndexAny(maskEscapedCharacters(line), "=:")
+		if separator < 0 {
+			properties[unescapeJavaProperty(strings.TrimSpace(line))] = ""
+			continue
+		}
+		key := strings.TrimSpace(line[:separator])
+		value := strings.TrimLeft(line[separator+1:], " \t")
+		properties[unescapeJavaProperty(key)] = unescapeJavaProperty(value)
+	}
+	return properties
+}
+
+// maskEscapedCharacters replaces escaped characters, so that escaped separators are not found.
+func maskEscapedCharacters(line string) string {
+	masked := []byte(line)
+	for i := 0; i < len(masked)-1; i++ {
+		if masked[i] == '\\' {
+			masked[i+1] = '_'
+			i++
+		}
+	}
+	return string(masked)
+}
+
+func unescapeJavaProperty(value string) string {
+	var unescaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+			switch value[i] {
+			case 't':
+				unescaped.WriteByte('\t')
+			case 'n':
+				unescaped.WriteByte('\n')
+			default:
+				unescaped.WriteByte(value[i])
+			}
+			continue
+		}
+		unescaped.WriteByte(value[i])
+	}
+	return unescaped.String()
+}
+
+// formatSettings renders settings as sorted key=value pairs for the csv file.
+func formatSettings(settings map[string]string) string {
+	var pairs []string
+	for _, key := range sortedKeys(settings) {
+		pairs = append(pairs, key+"="+settings[key])
+	}
+	return strings.Join(pairs, ";")
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
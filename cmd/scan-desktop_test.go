@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseJavaProperties(t *testing.T) {
+	content := "#deployment.properties\r\n" +
+		"#Mon Oct 19 17:09:11 CEST 2026\r\n" +
+		"deployment.security.level=VERY_HIGH\r\n" +
+		"deployment.expiration.check.enabled = false\r\n" +
+		"deployment.javaws.jre.0.path=C\\:\\\\Program Files\\\\Java\\\\jre1.8.0_202\\\\bin\\\\javaw.exe\r\n" +
+		"deployment.user.security.exception.sites=https\\://intranet.example.com/app,\\\r\n" +
+		"    https\\://legacy.example.com\r\n" +
+		"! comment\r\n" +
+		"deployment.webjava.enabled: true\r\n"
+	want := map[string]string{
+		"deployment.security.level":                "VERY_HIGH",
+		"deployment.expiration.check.enabled":      "false",
+		"deployment.javaws.jre.0.path":             `C:\Program Files\Java\jre1.8.0_202\bin\javaw.exe`,
+		"deployment.user.security.exception.sites": "https://intranet.example.com/app,https://legacy.example.com",
+		"deployment.webjava.enabled":               "true",
+	}
+	if got := parseJavaProperties(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseJavaProperties() = %v, want %v", got, want)
+	}
+}
+
+func Test_findDesktopComponents(t *testing.T) {
+	root := t.TempDir()
+	writeFixtureFiles(t, root, map[string]string{
+		"jre1.8.0_202/bin/java":                                  "#!/bin/sh\n",
+		"jre1.8.0_202/bin/javaws":                                "#!/bin/sh\n",
+		"Common Files/Java/Java Update/jusched.exe":              "MZ",
+		"etc/.java/deployment/deployment.properties":             "deployment.security.level=HIGH\n",
+		"home/alice/.java/deployment/deployment.properties":      "deployment.security.level=VERY_HIGH\n",
+		"home/bob/.java/deployment/deployment.properties.backup": "",
+	})
+	javaHome := filepath.Join(root, "jre1.8.0_202")
+	runtimeInfo := JavaInfo{DetectionMethod: FileSystem, Exe: filepath.Join(javaHome, "bin", "java"), Valid: true, MajorVersion: 8, BuildNumber: 202}
+	deploymentProperties := []userFile{
+		{Path: filepath.Join(root, "etc", ".java", "deployment", "deployment.properties")},
+		{Path: filepath.Join(root, "home", "alice", ".java", "deployment", "deployment.properties"), Username: "alice"},
+		{Path: filepath.Join(root, "home", "bob", ".java", "deployment", "deployment.properties"), Username: "bob"},
+	}
+	updateSchedulers := []string{
+		filepath.Join(root, "Common Files (x86)", "Java", "Java Update", "jusched.exe"),
+		filepath.Join(root, "Common Files", "Java", "Java Update", "jusched.exe"),
+	}
+
+	found := findDesktopComponents([]JavaInfo{runtimeInfo}, updateSchedulers, deploymentProperties)
+	var got []string
+	for _, info := range found {
+		relativePath, _ := filepath.Rel(root, info.ComponentPath)
+		got = append(got, info.Component+" "+filepath.ToSlash(relativePath)+" "+info.Username+" "+info.Settings["deployment.security.level"])
+	}
+	want := []string{
+		"java-web-start jre1.8.0_202/bin/javaws  ",
+		"update-scheduler Common Files/Java/Java Update/jusched.exe  ",
+		"deployment-properties etc/.java/deployment/deployment.properties  HIGH",
+		"deployment-properties home/alice/.java/deployment/deployment.properties alice VERY_HIGH",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("findDesktopComponents() = %q, want %q", got, want)
+	}
+
+	component := found[0]
+	if component.DetectionMethod != DesktopComponents || component.Exe != runtimeInfo.Exe || !component.Valid ||
+		component.MajorVersion != 8 || component.BuildNumber != 202 {
+		t.Errorf("findDesktopComponents() = %+v, want the information about the runtime", component)
+	}
+	// the runtime of the component is no additional java installation
+	if isJavaInstallation(component) || !isJavaInstallation(runtimeInfo) {
+		t.Errorf("isJavaInstallation() = %v for the component, %v for the runtime, want false and true",
+			isJavaInstallation(component), isJavaInstallation(runtimeInfo))
+	}
+	if violations, _ := evaluatePolicy(&Policy{AllowedVendors: []string{"Eclipse Adoptium"}}, found); len(violations) != 0 {
+		t.Errorf("evaluatePolicy() of desktop components = %v, want no violations", violations)
+	}
+}
+
+func Test_deploymentPropertiesFiles(t *testing.T) {
+	home := filepath.Join("home", "alice")
+	files := deploymentPropertiesFiles([]string{home})
+	if len(files) != 2 || files[0].Username != "" || files[1].Username != "alice" ||
+		!strings.HasPrefix(files[1].Path, home) || filepath.Base(files[1].Path) != "deployment.properties" {
+		t.Errorf("deploymentPropertiesFiles() = %+v, want the system wide file and the one of alice", files)
+	}
+}
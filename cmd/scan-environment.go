@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+var detectEnvironmentConfig bool
+
+// javaHomeVariables point to the home directory of a java installation.
+var javaHomeVariables = []string{"JAVA_HOME", "JRE_HOME", "JDK_HOME"}
+
+// systemEnvironmentFiles are the shell scripts and environment files setting the environment of all users and services.
+var systemEnvironmentFiles = []string{
+	"/etc/environment", "/etc/profile", "/etc/profile.d/*.sh", "/etc/bash.bashrc", "/etc/bashrc", "/etc/zsh/zshenv",
+	"/etc/default/*", "/etc/sysconfig/*",
+	"/opt/*/bin/setenv.sh", "/opt/*/*/bin/setenv.sh", "/usr/share/tomcat*/bin/setenv.sh", "/var/lib/tomcat*/bin/setenv.sh",
+}
+
+// userEnvironmentFiles are the shell rc files in the home directory of a user.
+var userEnvironmentFiles = []string{".profile", ".bash_profile", ".bash_login", ".bashrc", ".zshenv", ".zprofile", ".zshrc"}
+
+// configuredJava is a java installation a variable of an environment configuration points to.
+type configuredJava struct {
+	Source      string
+	Variable    string
+	JavaBinary  string
+	Username    string
+	ServiceName string
+	ErrorText   string
+}
+
+func detectEnvironmentConfigMain() []JavaInfo {
+	log.Infof("Starting detection '%s'...", EnvironmentConfig)
+	var result []JavaInfo
+	scanTimestamp := time.Now()
+	hostname, _ := os.Hostname()
+
+	var configured []configuredJava
+	for _, pattern := range systemEnvironmentFiles {
+		files, _ := filepath.Glob(pattern)
+		for _, file := range files {
+			configured = append(configured, findConfiguredJava(file, map[string]string{}, configuredJava{})...)
+		}
+	}
+	configured = append(configured, findConfiguredJavaOfUsers(userHomeDirectories())...)
+	configured = append(configured, findConfiguredJavaOfSystemdUnits()...)
+
+	analyzed := map[string]JavaInfo{}
+	for _, java := range configured {
+		var info JavaInfo
+		if java.ErrorText != "" {
+			log.Warnf("%s:%s: %s", java.Source, java.Variable, java.ErrorText)
+			info = JavaInfo{Exe: java.JavaBinary, ErrorText: java.ErrorText}
+		} else {
+			info = analyzeJavaBinaryOnce(analyzed, java.JavaBinary)
+		}
+		info.DetectionMethod = EnvironmentConfig
+		info.ScanTimestamp = scanTimestamp
+		info.Hostname = hostname
+		info.Username = java.Username
+		info.ServiceName = java.ServiceName
+		info.ConfigSource = java.Source + ":" + java.Variable
+		log.Infof("%s points to java binary %s", info.ConfigSource, java.JavaBinary)
+		result = append(result, info)
+	}
+	log.Infof("number of java installations configured in environment configurations: %d!", len(result))
+	return result
+}
+
+// findConfiguredJava evaluates the assignments of a shell script or environment file
+// and returns the java installations the java home and PATH variables point to.
+func findConfiguredJava(file string, environment map[string]string, template configuredJava) []configuredJava {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	template.Source = file
+	return findConfiguredJavaInAssignments(string(content), environment, template)
+}
+
+// findConfiguredJavaOfUsers evaluates the shell rc files in the home directories of the users.
+func findConfiguredJavaOfUsers(homes []string) []configuredJava {
+	var result []configuredJava
+	for _, home := range homes {
+		username := filepath.Base(home)
+		for _, name := range userEnvironmentFiles {
+			environment := map[string]string{"HOME": home, "USER": username}
+			result = append(result, findConfiguredJava(filepath.Join(home, name), environment, configuredJava{Username: username})...)
+		}
+	}
+	return result
+}
+
+// findConfiguredJavaInAssignments returns the java installations the assignments point to.
+// A java home without java binary is returned with an error text, a PATH without java binary is ignored.
+func findConfiguredJavaInAssignments(content string, environment map[string]string, template configuredJava) []configuredJava {
+	var result []configuredJava
+	for _, variable := range uniqueStrings(evaluateShellAssignments(content, environment)) {
+		java := template
+		java.Variable = variable
+		if containsFold(javaHomeVariables, variable) {
+			java.JavaBinary = filepath.Join(environment[variable], "bin", javaBinaryName())
+			if stat, err := os.Stat(java.JavaBinary); err != nil || stat.IsDir() {
+				java.ErrorText = fmt.Sprintf("%s=%s does not contain the java binary bin/%s", variable, environment[variable], javaBinaryName())
+			}
+		} else if variable == "PATH" {
+			java.JavaBinary = lookPathIn(environment[variable])
+		}
+		if environment[variable] != "" && java.JavaBinary != "" {
+			result = append(result, java)
+		}
+	}
+	return result
+}
+
+// findConfiguredJavaOfSystemdUnits evaluates the Environment= and EnvironmentFile= settings of the systemd service units.
+func findConfiguredJavaOfSystemdUnits() []configuredJava {
+	var result []configuredJava
+	for _, unit := range readSystemdServiceUnits() {
+		environment := map[string]string{}
+		template := configuredJava{ServiceName: unit.Name, Username: unit.setting("User")}
+		for _, file := range unit.Files {
+			template.Source = file
+			result = append(result, findConfiguredJavaInUnitFile(unit.Contents[file], environment, template)...)
+		}
+	}
+	return result
+}
+
+func findConfiguredJavaInUnitFile(content string, environment map[string]string, template configuredJava) []configuredJava {
+	var result []configuredJava
+	for _, line := range strings.Split(content, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		switch {
+		case !found:
+		case key == "Environment":
+			result = append(result, findConfiguredJavaInAssignments(parseSystemdEnvironment(value), environment, template)...)
+		case key == "EnvironmentFile":
+			environmentFile := template
+			environmentFile.Source = strings.TrimPrefix(value, "-")
+			result = append(result, findConfiguredJava(environmentFile.Source, environment, environmentFile)...)
+		}
+	}
+	return result
+}
+
+// lookPathIn returns the java binary found first in the directories of the path list, an empty string if there is none.
+func lookPathIn(pathList string) string {
+	for _, directory := range filepath.SplitList(pathList) {
+		if directory == "" {
+			continue
+		}
+		javaBinary := filepath.Join(directory, javaBinaryName())
+		if info, err := os.Stat(javaBinary); err == nil && !info.IsDir() {
+			return javaBinary
+		}
+	}
+	return ""
+}
+
+func javaBinaryName() string {
+	if runtime.GOOS == "windows" {
+		return "java.exe"
+	}
+	return "java"
+}
+
+// userHomeDirectories returns the home directories of the local users.
+func userHomeDirectories() []string {
+	var homes []string
+	switch runtime.GOOS {
+	case "windows":
+		homes, _ = filepath.Glob(filepath.Join(os.Getenv("SystemDrive")+`\`, "Users", "*"))
+	case "darwin":
+		homes, _ = filepath.Glob("/Users/*")
+	default:
+		homes, _ = filepath.Glob("/home/*")
+		homes = append(homes, "/root")
+	}
+	return homes
+}
+
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
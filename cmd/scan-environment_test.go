@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// writeFixtureFiles writes the files given by their path below the root directory.
+func writeFixtureFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		fileName := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fileName, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_findConfiguredJavaOfSystemdUnits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd services do not exist on windows")
+	}
+	root := t.TempDir()
+	jdk := filepath.Join(root, "opt", "jdk-17")
+	writeFixtureFiles(t, root, map[string]string{
+		"opt/jdk-17/bin/java": "#!/bin/sh\n",
+		"usr/lib/systemd/system/app.service": "[Service]\nUser=app\nEnvironment=\"JAVA_HOME=" + jdk + "\" \"JAVA_OPTS=-Xmx1g\"\n" +
+			"EnvironmentFile=-" + filepath.Join(root, "etc", "default", "app") + "\nExecStart=/opt/app/bin/start\n",
+		"etc/default/app": "JRE_HOME=" + filepath.Join(root, "opt", "jre-8") + "\n",
+		"etc/systemd/system/app.service.d/override.conf": "[Service]\nEnvironment=PATH=" + filepath.Join(jdk, "bin") + ":/usr/bin\n",
+		"usr/lib/systemd/system/sshd.service":            "[Service]\nExecStart=/usr/sbin/sshd -D\n",
+	})
+	defer func(directories []string) { systemdUnitDirectories = directories }(systemdUnitDirectories)
+	systemdUnitDirectories = []string{filepath.Join(root, "etc", "systemd", "system"), filepath.Join(root, "usr", "lib", "systemd", "system")}
+
+	want := []configuredJava{
+		{Source: filepath.Join(root, "usr", "lib", "systemd", "system", "app.service"), Variable: "JAVA_HOME",
+			JavaBinary: filepath.Join(jdk, "bin", "java"), Username: "app", ServiceName: "app"},
+		{Source: filepath.Join(root, "etc", "default", "app"), Variable: "JRE_HOME", JavaBinary: filepath.Join(root, "opt", "jre-8", "bin", "java"),
+			Username: "app", ServiceName: "app", ErrorText: "JRE_HOME=" + filepath.Join(root, "opt", "jre-8") + " does not contain the java binary bin/java"},
+		{Source: filepath.Join(root, "etc", "systemd", "system", "app.service.d", "override.conf"), Variable: "PATH",
+			JavaBinary: filepath.Join(jdk, "bin", "java"), Username: "app", ServiceName: "app"},
+	}
+	if got := findConfiguredJavaOfSystemdUnits(); !reflect.DeepEqual(got, want) {
+		t.Errorf("findConfiguredJavaOfSystemdUnits() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_findConfiguredJavaOfUsers(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell rc files are not evaluated on windows")
+	}
+	root := t.TempDir()
+	alice, bob := filepath.Join(root, "home", "alice"), filepath.Join(root, "home", "bob")
+	writeFixtureFiles(t, root, map[string]string{
+		"home/alice/jdk-21/bin/java": "#!/bin/sh\n",
+		"home/alice/.profile":        "PATH=\"$HOME/jdk-21/bin:$PATH\"\n",
+		"home/alice/.bashrc":         "export JAVA_HOME=$HOME/jdk-21\n",
+		"home/bob/.zshrc":            "export JAVA_HOME=/opt/jdk-$USER\n",
+		"home/bob/.bash_history":     "export JAVA_HOME=/opt/jdk-11\n",
+	})
+
+	want := []configuredJava{
+		{Source: filepath.Join(alice, ".profile"), Variable: "PATH", JavaBinary: filepath.Join(alice, "jdk-21", "bin", "java"), Username: "alice"},
+		{Source: filepath.Join(alice, ".bashrc"), Variable: "JAVA_HOME", JavaBinary: filepath.Join(alice, "jdk-21", "bin", "java"), Username: "alice"},
+		{Source: filepath.Join(bob, ".zshrc"), Variable: "JAVA_HOME", JavaBinary: "/opt/jdk-bob/bin/java", Username: "bob",
+			ErrorText: "JAVA_HOME=/opt/jdk-bob does not contain the java binary bin/java"},
+	}
+	if got := findConfiguredJavaOfUsers([]string{alice, bob}); !reflect.DeepEqual(got, want) {
+		t.Errorf("findConfiguredJavaOfUsers() = %+v, want %+v", got, want)
+	}
+}
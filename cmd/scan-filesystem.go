@@ -1,10 +1,9 @@
 package cmd
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
-	"regexp"
-	"strings"
 	"time"
 )
 
@@ -12,18 +11,35 @@ func detectFileSystemScanMain() []JavaInfo {
 	log.Infof("Starting detection '%s' while excluding %s...", FileSystem, detectFileSystemScanExcludePaths)
 	var result []JavaInfo
 	scanTimestamp := time.Now()
+	walker, err := newFileSystemWalker(fileSystemWalkerOptions{
+		ExcludePaths:   detectFileSystemScanExcludePaths,
+		ExcludeGlobs:   detectFileSystemScanExcludeGlobs,
+		IncludeGlobs:   detectFileSystemScanIncludeGlobs,
+		OneFileSystem:  detectFileSystemScanOneFileSystem,
+		MaxDepth:       detectFileSystemScanMaxDepth,
+		SkipMountTypes: detectFileSystemScanSkipMountTypes,
+		FollowSymlinks: detectFileSystemScanFollowSymlinks,
+	})
+	if err != nil {
+		log.Errorf("Not starting detection '%s', invalid exclude or include pattern: %s", FileSystem, err)
+		return result
+	}
+	cache := &analysisCache{entries: map[string]analysisCacheEntry{}}
+	if !noCache {
+		cache = loadAnalysisCache(cacheFile)
+	}
 	for _, rootPath := range detectFileSystemScanRootPaths {
 		count := 0
 		log.Infof("Scanning started at root path %s...", rootPath)
 
-		targetFiles, _ := collectFiles(rootPath, detectFileSystemScanExcludePaths)
+		targetFiles, archiveFiles := collectFiles(walker, rootPath)
 
 		log.Infof("File system scan found java installations: %v", targetFiles)
 		for _, javaBinary := range targetFiles {
 			info := JavaInfo{ScanTimestamp: scanTimestamp, DetectionMethod: FileSystem}
 			info.Hostname, _ = os.Hostname()
 			info.Exe = javaBinary
-			analyzeJavaBinaryMain(&info)
+			analyzeJavaBinaryCached(cache, &info)
 
 			// include file in any case. info.valid will state if file is a valid java binary. info.
 			result = append(result, info)
@@ -31,31 +47,49 @@ func detectFileSystemScanMain() []JavaInfo {
 				count = count + 1
 			}
 		}
+		for _, archiveFile := range archiveFiles {
+			archiveResult, archiveErrors, err := scanArchive(archiveFile, detectFileSystemScanArchivesMaxDepth)
+			if err != nil {
+				log.Warnf("Cannot scan archive %s: %s", archiveFile, err)
+				notScannedPaths = append(notScannedPaths, notScannedPath{DetectionMethod: FileSystemArchive, Path: archiveFile, Reason: err.Error()})
+				continue
+			}
+			for _, archiveErr := range archiveErrors {
+				notScannedPaths = append(notScannedPaths, notScannedPath{DetectionMethod: FileSystemArchive, Path: archiveErr.Path, Reason: archiveErr.Err.Error()})
+			}
+			for _, info := range archiveResult {
+				info.ScanTimestamp = scanTimestamp
+				log.Infof("Found java installation in archive %s: %s", info.ArchivePath, info.ArchiveInnerPath)
+				result = append(result, info)
+				if info.Valid {
+					count = count + 1
+				}
+			}
+		}
 		log.Infof("number of valid java installations found by filesystem scan below root path %s: %d!", rootPath, count)
 	}
+	if !noCache {
+		if err := cache.save(); err != nil {
+			log.Warnf("Cannot write cache file %s: %s", cacheFile, err)
+		}
+	}
+	for _, skipped := range walker.skipped {
+		notScannedPaths = append(notScannedPaths, notScannedPath{DetectionMethod: FileSystem, Path: skipped.Path, Reason: "skipped " + skipped.Reason})
+	}
+	for _, walkErr := range walker.errors {
+		notScannedPaths = append(notScannedPaths, notScannedPath{DetectionMethod: FileSystem, Path: walkErr.Path, Reason: walkErr.Err.Error()})
+	}
 	return result
 }
 
-func collectFiles(dir string, excludeList []string) (fileList []string, err error) {
-	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if len(excludeList) > 0 && regexp.MustCompile(strings.Join(excludeList, "|")).Match([]byte(path)) {
-			//fmt.Printf("%s\n", path)
-			return nil
-		}
-
-		if info.IsDir() {
-			return nil
-		}
+func collectFiles(walker *fileSystemWalker, dir string) (fileList []string, archiveList []string) {
+	walker.walk(dir, func(path string, entry fs.DirEntry) {
 		file := filepath.Base(path)
-		if strings.EqualFold(file, "java") || strings.EqualFold(file, "java.exe") {
+		if isJavaBinaryName(file) {
 			fileList = append(fileList, path)
+		} else if detectFileSystemScanArchives && detectArchiveKind(file) != noArchive {
+			archiveList = append(archiveList, path)
 		}
-
-		return nil
 	})
-	if err != nil {
-		log.Fatalf("walk error [%v]", err)
-		return nil, err
-	}
-	return fileList, nil
+	return fileList, archiveList
 }
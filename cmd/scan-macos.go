@@ -0,0 +1,10 @@
+//go:build !darwin
+// +build !darwin
+
+package cmd
+
+func detectMacOSJavaVirtualMachinesMain() []JavaInfo {
+	log.Warnf("Not starting detection '%s', since this is only implemented for macOS!", MacOSJavaVirtualMachines)
+	var result []JavaInfo
+	return result
+}
@@ -0,0 +1,34 @@
+//go:build darwin
+
+package cmd
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+func detectMacOSJavaVirtualMachinesMain() []JavaInfo {
+	log.Infof("Starting detection '%s'...", MacOSJavaVirtualMachines)
+	directories := append([]string{}, javaVirtualMachinesDirectories...)
+	for _, home := range userHomeDirectories() {
+		directories = append(directories, filepath.Join(home, "Library", "Java", "JavaVirtualMachines"))
+	}
+	jvms := findJavaVirtualMachines(directories)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "/usr/libexec/java_home", "-X").Output()
+	if err != nil {
+		// java_home fails as well, if no java virtual machine is registered
+		log.Warnf("Cannot list the java virtual machines registered via java_home -X, using the bundles found only: %s", err)
+	} else {
+		registered, err := parseJavaHomeList(out)
+		if err != nil {
+			log.Warnf("Cannot parse the output of java_home -X: %s", err)
+		}
+		jvms = mergeJavaVirtualMachines(jvms, registered)
+	}
+	return macOSJavaVirtualMachineFindings(jvms)
+}
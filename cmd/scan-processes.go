@@ -32,6 +32,13 @@ func extractJavaProcessInfos() []JavaInfo {
 			if exe != "" {
 				info.Exe = exe
 				analyzeJavaBinaryMain(&info)
+				commandLine, _ := p1.CmdlineSlice()
+				info.CommercialFeatures = parseCommercialFeatures(commandLine)
+				workingDir, _ := p1.Cwd()
+				info.ApplicationName, info.ApplicationVersion = detectApplication(commandLine, workingDir, exe)
+				if info.ApplicationName != "" {
+					log.Infof("Process %d runs %s %s on java binary %s", p1.Pid, info.ApplicationName, info.ApplicationVersion, exe)
+				}
 			}
 			result = append(result, info)
 		}
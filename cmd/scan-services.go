@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var detectServices bool
+
+// defaultServicePath is the PATH systemd starts services with.
+const defaultServicePath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// sysVInitDirectory contains the SysV init scripts, the rc directories of the runlevels are located next to it.
+var sysVInitDirectory = "/etc/init.d"
+
+// sysVInitVariables are the variables init scripts use for the java binary, besides the java home variables.
+var sysVInitVariables = []string{"JAVA", "JAVA_CMD", "JAVA_BIN", "JAVA_EXE"}
+
+// maxServiceScriptSize limits the size of start scripts, which are read completely.
+const maxServiceScriptSize = 1024 * 1024
+
+// javaReference matches the java binary or the java variables in a start script, e.g. "$JAVA_HOME/bin/java" or "$JAVA".
+var javaReference = regexp.MustCompile(`\bjava\b|\$\{?(JAVA|JRE|JDK)\w*`)
+
+// javaService is a systemd or SysV init service launching a JVM.
+type javaService struct {
+	Name       string
+	State      string
+	Username   string
+	Source     string
+	JavaBinary string
+	ErrorText  string
+}
+
+func detectServicesMain() []JavaInfo {
+	log.Infof("Starting detection '%s'...", Services)
+	var result []JavaInfo
+	scanTimestamp := time.Now()
+	hostname, _ := os.Hostname()
+
+	services, systemdNames := findJavaSystemdServices()
+	services = append(services, findJavaSysVServices(systemdNames)...)
+	services = append(services, findJavaWindowsServices()...)
+
+	analyzed := map[string]JavaInfo{}
+	for _, service := range services {
+		var info JavaInfo
+		if service.ErrorText != "" {
+			log.Warnf("Service %s: %s", service.Name, service.ErrorText)
+			info = JavaInfo{Exe: service.JavaBinary, ErrorText: service.ErrorText}
+		} else {
+			info = analyzeJavaBinaryOnce(analyzed, service.JavaBinary)
+		}
+		info.DetectionMethod = Services
+		info.ScanTimestamp = scanTimestamp
+		info.Hostname = hostname
+		info.Username = service.Username
+		info.ServiceName = service.Name
+		info.ServiceState = service.State
+		info.ConfigSource = service.Source
+		log.Infof("Service %s (%s) runs java binary %s as user %s", service.Name, service.State, service.JavaBinary, service.Username)
+		result = append(result, info)
+	}
+	log.Infof("number of detected java services: %d!", len(result))
+	return result
+}
+
+// analyzeJavaBinaryOnce analyzes a java binary referred to by several findings only once.
+func analyzeJavaBinaryOnce(analyzed map[string]JavaInfo, javaBinary string) JavaInfo {
+	info, found := analyzed[javaBinary]
+	if !found {
+		info = JavaInfo{Exe: javaBinary}
+		analyzeJavaBinaryMain(&info)
+		analyzed[javaBinary] = info
+	}
+	return info
+}
+
+// findJavaSystemdServices returns the service units, whose ExecStart launches a JVM, and the names of all service units.
+func findJavaSystemdServices() ([]javaService, map[string]bool) {
+	var services []javaService
+	names := map[string]bool{}
+	for _, unit := range readSystemdServiceUnits() {
+		names[unit.Name] = true
+		environment := unit.environment()
+		javaBinary := ""
+		for _, execStart := range unit.settings("ExecStart") {
+			if javaBinary = resolveServiceJavaBinary(execStart, environment); javaBinary != "" {
+				break
+			}
+		}
+		if javaBinary == "" {
+			continue
+		}
+		username := unit.setting("User")
+		if username == "" {
+			username = "root"
+		}
+		services = append(services, javaService{Name: unit.Name, State: systemdServiceState(unit), Username: username,
+			Source: unit.Files[0], JavaBinary: javaBinary})
+	}
+	return services, names
+}
+
+// resolveServiceJavaBinary returns the java binary a command line of a service launches,
+// an empty string if it does not launch a JVM or the JVM cannot be resolved.
+func resolveServiceJavaBinary(commandLine string, environment map[string]string) string {
+	// prefixes like '-' for ignoring failures
+	commandLine = strings.TrimLeft(commandLine, "-@:+!")
+	words := strings.Fields(expandShellVariables(commandLine, environment))
+	if len(words) == 0 {
+		return ""
+	}
+	executable := strings.Trim(words[0], `"'`)
+	if isJavaBinaryName(filepath.Base(executable)) {
+		if filepath.IsAbs(executable) {
+			return executable
+		}
+		return lookPathIn(servicePath(environment))
+	}
+	// scripts like catalina.sh launch the JVM of the java home of the unit or set the java home themselves,
+	// a java home in the environment of other executables does not make them a JVM
+	if script, ok := readServiceScript(executable); ok && javaReference.Match(script) {
+		scriptEnvironment := map[string]string{}
+		for name, value := range environment {
+			scriptEnvironment[name] = value
+		}
+		evaluateShellAssignments(string(script), scriptEnvironment)
+		if javaBinary := javaBinaryOfEnvironment(scriptEnvironment); javaBinary != "" {
+			return javaBinary
+		}
+	}
+	for _, word := range words[1:] {
+		if word == "-jar" || strings.HasSuffix(word, ".jar") {
+			return lookPathIn(servicePath(environment))
+		}
+	}
+	return ""
+}
+
+// readServiceScript reads the executable of a service, if it is a script. Binaries are not evaluated as scripts.
+func readServiceScript(executable string) ([]byte, bool) {
+	stat, err := os.Stat(executable)
+	if err != nil || !stat.Mode().IsRegular() || stat.Size() > maxServiceScriptSize {
+		return nil, false
+	}
+	content, err := os.ReadFile(executable)
+	if err != nil || bytes.HasPrefix(content, []byte("\x7fELF")) || bytes.IndexByte(content, 0) >= 0 {
+		return nil, false
+	}
+	return content, true
+}
+
+// javaBinaryOfEnvironment returns the java binary the java home or java binary variables point to.
+func javaBinaryOfEnvironment(environment map[string]string) string {
+	for _, variable := range javaHomeVariables {
+		if javaHome := environment[variable]; javaHome != "" {
+			return filepath.Join(javaHome, "bin", javaBinaryName())
+		}
+	}
+	for _, variable := range sysVInitVariables {
+		if javaBinary := environment[variable]; javaBinary != "" && isJavaBinaryName(filepath.Base(javaBinary)) {
+			return javaBinary
+		}
+	}
+	return ""
+}
+
+func servicePath(environment map[string]string) string {
+	if path := environment["PATH"]; path != "" {
+		return path
+	}
+	return defaultServicePath
+}
+
+// systemdServiceState asks systemd for the enablement and active state of the unit, e.g. "enabled/active".
+// Without a running systemd, the enablement is read from the file system and the active state is unknown.
+func systemdServiceState(unit systemdUnit) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "systemctl", "show", "--property=UnitFileState", "--property=ActiveState", unit.Name+".service").Output()
+	if err == nil {
+		properties := parseReleaseFile(string(out))
+		if properties["UnitFileState"] != "" && properties["ActiveState"] != "" {
+			return properties["UnitFileState"] + "/" + properties["ActiveState"]
+		}
+	}
+	if unit.isEnabled() {
+		return "enabled/unknown"
+	}
+	return "disabled/unknown"
+}
+
+// findJavaSysVServices returns the SysV init scripts, that set a java home or java binary variable,
+// except the ones replaced by a systemd unit of the same name.
+func findJavaSysVServices(systemdNames map[string]bool) []javaService {
+	var services []javaService
+	scripts, _ := filepath.Glob(filepath.Join(sysVInitDirectory, "*"))
+	for _, script := range scripts {
+		name := filepath.Base(script)
+		if systemdNames[name] {
+			continue
+		}
+		content, err := os.ReadFile(script)
+		if err != nil || len(content) > maxServiceScriptSize {
+			continue
+		}
+		environment := map[string]string{}
+		assigned := evaluateShellAssignments(string(content), environment)
+		javaBinary := javaBinaryOfEnvironment(environment)
+		if javaBinary == "" {
+			continue
+		}
+		username := "root"
+		for _, variable := range assigned {
+			if strings.HasSuffix(variable, "USER") && environment[variable] != "" {
+				username = environment[variable]
+				break
+			}
+		}
+		state := "disabled/unknown"
+		if links, _ := filepath.Glob(filepath.Join(filepath.Dir(sysVInitDirectory), "rc[2345].d", "S*"+name)); len(links) > 0 {
+			state = "enabled/unknown"
+		}
+		services = append(services, javaService{Name: name, State: state, Username: username, Source: script, JavaBinary: javaBinary})
+	}
+	return services
+}
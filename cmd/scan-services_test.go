@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func Test_resolveServiceJavaBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd services do not exist on windows")
+	}
+	root := t.TempDir()
+	writeFixtureFiles(t, root, map[string]string{
+		"catalina.sh": "#!/bin/sh\nJAVA_HOME=/opt/jdk-11\nexec \"$JAVA_HOME/bin/java\" org.apache.catalina.startup.Bootstrap\n",
+		"startup.sh":  "#!/bin/sh\nexec \"$JRE_HOME/bin/java\" -jar app.jar\n",
+		"backup.sh":   "#!/bin/sh\nJAVA_HOME=/opt/jdk-11\nexec rsync -a /srv/ /backup/\n",
+		"agent":       "\x7fELF\x02\x01\x01\x00JAVA_HOME=/opt/jdk-8 java",
+	})
+	startScript := filepath.Join(root, "catalina.sh")
+
+	tests := []struct {
+		name        string
+		execStart   string
+		environment map[string]string
+		want        string
+	}{
+		{"java binary", "/usr/lib/jvm/java-17/bin/java -Xmx1g -jar /opt/app/app.jar", map[string]string{}, "/usr/lib/jvm/java-17/bin/java"},
+		{"java binary from environment", "-${JAVA_HOME}/bin/java -jar app.jar", map[string]string{"JAVA_HOME": "/opt/jdk-21"}, "/opt/jdk-21/bin/java"},
+		{"script with environment", filepath.Join(root, "startup.sh"), map[string]string{"JRE_HOME": "/opt/jre-8"}, "/opt/jre-8/bin/java"},
+		{"script setting java home", startScript + " run", map[string]string{}, "/opt/jdk-11/bin/java"},
+		{"no jvm", "/usr/sbin/sshd -D", map[string]string{}, ""},
+		{"binary with java home", filepath.Join(root, "agent") + " --daemon", map[string]string{"JAVA_HOME": "/opt/jdk-17"}, ""},
+		{"script without java", filepath.Join(root, "backup.sh"), map[string]string{"JAVA_HOME": "/opt/jdk-17"}, ""},
+		{"missing executable with java home", "/opt/app/bin/server", map[string]string{"JAVA_HOME": "/opt/jdk-17"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveServiceJavaBinary(tt.execStart, tt.environment); got != tt.want {
+				t.Errorf("resolveServiceJavaBinary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findJavaSysVServices(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SysV init scripts do not exist on windows")
+	}
+	root := t.TempDir()
+	writeFixtureFiles(t, root, map[string]string{
+		"etc/init.d/tomcat": "#!/bin/sh\nCATALINA_HOME=/opt/tomcat\nJAVA_HOME=/opt/jdk-8\nTOMCAT_USER=tomcat\nDB_USER=shop\n",
+		// the first assignment of a variable ending with USER is taken as the user of the service
+		"etc/init.d/jenkins":    "#!/bin/sh\nJENKINS_USER=\nRUN_AS_USER=jenkins\nJAVA=/usr/lib/jvm/java-11/bin/java\n",
+		"etc/init.d/activemq":   "#!/bin/sh\nJAVA_CMD=/opt/jdk-17/bin/java\nUSER=\"${ACTIVEMQ_USER:-activemq}\"\n",
+		"etc/init.d/app":        "#!/bin/sh\nJAVA_HOME=/opt/jdk-21\n",
+		"etc/init.d/sshd":       "#!/bin/sh\nDAEMON=/usr/sbin/sshd\n",
+		"etc/init.d/wrapper":    "#!/bin/sh\nJAVA=/usr/bin/wrapper\n",
+		"etc/rc3.d/S20tomcat":   "",
+		"etc/rc5.d/S10jenkins":  "",
+		"etc/rc0.d/K01activemq": "",
+	})
+	defer func(directory string) { sysVInitDirectory = directory }(sysVInitDirectory)
+	sysVInitDirectory = filepath.Join(root, "etc", "init.d")
+
+	script := func(name string) string { return filepath.Join(root, "etc", "init.d", name) }
+	want := []javaService{
+		{Name: "activemq", State: "disabled/unknown", Username: "activemq", Source: script("activemq"), JavaBinary: "/opt/jdk-17/bin/java"},
+		{Name: "jenkins", State: "enabled/unknown", Username: "jenkins", Source: script("jenkins"), JavaBinary: "/usr/lib/jvm/java-11/bin/java"},
+		{Name: "tomcat", State: "enabled/unknown", Username: "tomcat", Source: script("tomcat"), JavaBinary: "/opt/jdk-8/bin/java"},
+	}
+	// the app script is replaced by the systemd unit of the same name
+	if got := findJavaSysVServices(map[string]bool{"app": true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("findJavaSysVServices() = %+v, want %+v", got, want)
+	}
+}
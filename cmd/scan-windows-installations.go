@@ -0,0 +1,9 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+func readUninstallEntries() []uninstallEntry {
+	log.Warnf("Not reading the uninstall entries of '%s', since this is only implemented for windows!", WindowsInstallations)
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build windows
+
+package cmd
+
+import (
+	"golang.org/x/sys/windows/registry"
+)
+
+// readUninstallEntries reads the uninstall entries of the programs installed for all users.
+func readUninstallEntries() []uninstallEntry {
+	var entries []uninstallEntry
+	for _, path := range uninstallKeys {
+		k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.ENUMERATE_SUB_KEYS)
+		if err != nil {
+			log.Warnf("Error when reading path %s: %s", path, err.Error())
+			continue
+		}
+		names, err := k.ReadSubKeyNames(-1)
+		k.Close()
+		if err != nil {
+			log.Warnf("Error when reading subkeynames of path %s: %s", path, err.Error())
+			continue
+		}
+		for _, name := range names {
+			if entry, err := readUninstallEntry(path + `\` + name); err == nil {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+func readUninstallEntry(path string) (uninstallEntry, error) {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE)
+	if err != nil {
+		return uninstallEntry{}, err
+	}
+	defer k.Close()
+	entry := uninstallEntry{Key: path}
+	for name, value := range map[string]*string{"DisplayName": &entry.DisplayName, "DisplayVersion": &entry.DisplayVersion,
+		"Publisher": &entry.Publisher, "InstallLocation": &entry.InstallLocation, "InstallDate": &entry.InstallDate} {
+		*value, _, _ = k.GetStringValue(name)
+	}
+	return entry, nil
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+// readWindowsServiceConfigs returns no services, since the service control manager only exists on windows.
+func readWindowsServiceConfigs() []windowsServiceConfig {
+	return nil
+}
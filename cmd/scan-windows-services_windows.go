@@ -0,0 +1,137 @@
+//go:build windows
+
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// readWindowsServiceConfigs enumerates the win32 services of the service control manager
+// and reads the parameters of procrun and NSSM from the registry.
+func readWindowsServiceConfigs() []windowsServiceConfig {
+	manager, err := windows.OpenSCManager(nil, nil, windows.SC_MANAGER_CONNECT|windows.SC_MANAGER_ENUMERATE_SERVICE)
+	if err != nil {
+		log.Warnf("Cannot connect to the service control manager: %s", err)
+		return nil
+	}
+	defer windows.CloseServiceHandle(manager)
+
+	var configs []windowsServiceConfig
+	for _, status := range enumerateServices(manager) {
+		config := windowsServiceConfig{
+			Name:  windows.UTF16PtrToString(status.ServiceName),
+			State: status.ServiceStatusProcess.CurrentState,
+		}
+		if err := queryServiceConfig(manager, &config); err != nil {
+			log.Warnf("Cannot query the configuration of service %s: %s", config.Name, err)
+			continue
+		}
+		for _, key := range procrunKeys {
+			config.ProcrunKey = key + `\` + config.Name + `\Parameters\Java`
+			values := readRegistryStrings(config.ProcrunKey, "Jvm", "JavaHome")
+			if config.ProcrunJvm, config.ProcrunJavaHome = values[0], values[1]; config.ProcrunJvm != "" || config.ProcrunJavaHome != "" {
+				break
+			}
+		}
+		isProcrunAuto := config.ProcrunJvm == "" || strings.EqualFold(config.ProcrunJvm, "auto")
+		if isProcrunAuto && config.ProcrunJavaHome == "" && (config.ProcrunJvm != "" || containsProcrunCommand(splitWindowsCommandLine(config.ImagePath))) {
+			config.JavaSoftRuntimeLib = readJavaSoftRuntimeLib(config.ProcrunKey)
+		}
+		config.NssmApplication = readRegistryStrings(`SYSTEM\CurrentControlSet\Services\`+config.Name+`\Parameters`, "Application")[0]
+		configs = append(configs, config)
+	}
+	return configs
+}
+
+// readJavaSoftRuntimeLib returns the JVM library of the current version registered below JavaSoft like procrun does
+// for Jvm=auto: the RuntimeLib of the JRE, or the one in the JavaHome of the JDK. 32-bit procrun reads the 32-bit keys.
+func readJavaSoftRuntimeLib(procrunKey string) string {
+	javaSoft := `SOFTWARE\JavaSoft`
+	if strings.Contains(procrunKey, `\WOW6432Node\`) {
+		javaSoft = `SOFTWARE\WOW6432Node\JavaSoft`
+	}
+	// the keys of java 8 and older, followed by the ones of java 9 and newer
+	for _, runtimeKey := range []string{"Java Runtime Environment", "JRE"} {
+		if currentVersion := readRegistryStrings(javaSoft+`\`+runtimeKey, "CurrentVersion")[0]; currentVersion != "" {
+			if runtimeLib := readRegistryStrings(javaSoft+`\`+runtimeKey+`\`+currentVersion, "RuntimeLib")[0]; runtimeLib != "" {
+				return runtimeLib
+			}
+		}
+	}
+	for _, developmentKitKey := range []string{"Java Development Kit", "JDK"} {
+		if currentVersion := readRegistryStrings(javaSoft+`\`+developmentKitKey, "CurrentVersion")[0]; currentVersion != "" {
+			if javaHome := readRegistryStrings(javaSoft+`\`+developmentKitKey+`\`+currentVersion, "JavaHome")[0]; javaHome != "" {
+				return strings.TrimRight(javaHome, `\`) + `\bin\server\jvm.dll`
+			}
+		}
+	}
+	return ""
+}
+
+func enumerateServices(manager windows.Handle) []windows.ENUM_SERVICE_STATUS_PROCESS {
+	var bytesNeeded, servicesReturned uint32
+	buffer := make([]byte, 64*1024)
+	for {
+		err := windows.EnumServicesStatusEx(manager, windows.SC_ENUM_PROCESS_INFO, windows.SERVICE_WIN32, windows.SERVICE_STATE_ALL,
+			&buffer[0], uint32(len(buffer)), &bytesNeeded, &servicesReturned, nil, nil)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, windows.ERROR_MORE_DATA) {
+			log.Warnf("Cannot enumerate services: %s", err)
+			return nil
+		}
+		buffer = make([]byte, bytesNeeded)
+	}
+	if servicesReturned == 0 {
+		return nil
+	}
+	return unsafe.Slice((*windows.ENUM_SERVICE_STATUS_PROCESS)(unsafe.Pointer(&buffer[0])), servicesReturned)
+}
+
+func queryServiceConfig(manager windows.Handle, config *windowsServiceConfig) error {
+	name, err := windows.UTF16PtrFromString(config.Name)
+	if err != nil {
+		return err
+	}
+	service, err := windows.OpenService(manager, name, windows.SERVICE_QUERY_CONFIG)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseServiceHandle(service)
+	var bytesNeeded uint32
+	buffer := make([]byte, 8*1024)
+	for {
+		serviceConfig := (*windows.QUERY_SERVICE_CONFIG)(unsafe.Pointer(&buffer[0]))
+		err = windows.QueryServiceConfig(service, serviceConfig, uint32(len(buffer)), &bytesNeeded)
+		if err == nil {
+			config.ImagePath = windows.UTF16PtrToString(serviceConfig.BinaryPathName)
+			config.Account = windows.UTF16PtrToString(serviceConfig.ServiceStartName)
+			config.StartType = serviceConfig.StartType
+			return nil
+		}
+		if !errors.Is(err, windows.ERROR_INSUFFICIENT_BUFFER) {
+			return err
+		}
+		buffer = make([]byte, bytesNeeded)
+	}
+}
+
+// readRegistryStrings reads string values of a key below HKEY_LOCAL_MACHINE, empty strings for the ones, that do not exist.
+func readRegistryStrings(path string, names ...string) []string {
+	values := make([]string, len(names))
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE)
+	if err != nil {
+		return values
+	}
+	defer k.Close()
+	for i, name := range names {
+		values[i], _, _ = k.GetStringValue(name)
+	}
+	return values
+}
@@ -12,9 +12,22 @@ var detectRunningProcesses bool
 var detectFileSystemScan bool
 var detectFileSystemScanRootPaths []string
 var detectFileSystemScanExcludePaths []string
+var detectFileSystemScanExcludeGlobs []string
+var detectFileSystemScanIncludeGlobs []string
+var detectFileSystemScanOneFileSystem bool
+var detectFileSystemScanMaxDepth int
+var detectFileSystemScanSkipMountTypes []string
+var detectFileSystemScanFollowSymlinks bool
+var detectFileSystemScanArchives bool
+var detectFileSystemScanArchivesMaxDepth int
 
 var detectCurrentPath bool
+var fingerprint bool
+var knownBuildsFile string
 var appendToFindingsJson bool
+var pushToUrl string
+var outputDir string
+var csvPropertyColumns []string
 
 type DetectionMethod int64
 
@@ -24,6 +37,14 @@ const (
 	RunningProcesses
 	WindowsRegistry
 	CurrentPath
+	FileSystemArchive
+	DesktopComponents
+	EnvironmentConfig
+	Services
+	BuildConfig
+	CiTools
+	WindowsInstallations
+	MacOSJavaVirtualMachines
 )
 
 func (s DetectionMethod) String() string {
@@ -38,42 +59,150 @@ func (s DetectionMethod) String() string {
 		return "windows-registry"
 	case CurrentPath:
 		return "current-path"
+	case FileSystemArchive:
+		return "file-system-archive"
+	case DesktopComponents:
+		return "desktop-components"
+	case EnvironmentConfig:
+		return "environment-config"
+	case Services:
+		return "services"
+	case BuildConfig:
+		return "build-config"
+	case CiTools:
+		return "ci-tools"
+	case WindowsInstallations:
+		return "windows-installations"
+	case MacOSJavaVirtualMachines:
+		return "macos-java-virtual-machines"
 	}
 
 	return "unknown"
 }
 
 type JavaInfo struct {
-	DetectionMethod DetectionMethod
-	ScanTimestamp   time.Time
-	Hostname        string
-	Exe             string
-	Valid           bool
-	Username        string
-	Vendor          string
-	RuntimeName     string
-	MajorVersion    int
-	BuildNumber     int
-	ErrorText       string
+	DetectionMethod      DetectionMethod
+	ScanTimestamp        time.Time
+	Hostname             string
+	Exe                  string
+	ArchivePath          string
+	ArchiveInnerPath     string
+	Valid                bool
+	AnalysisMethod       AnalysisMethod
+	Username             string
+	Vendor               string
+	RuntimeName          string
+	MajorVersion         int
+	BuildNumber          int
+	Distribution         Distribution
+	JvmImplementation    JvmImplementation
+	VmName               string
+	VmVendor             string
+	VmVersion            string
+	VendorVersion        string
+	SpecificationVersion string
+	OsArch               string
+	BinaryOS             string
+	BinaryArch           string
+	BinaryBits           int
+	LicenseCategory      LicenseCategory
+	ExeSHA256            string
+	ExeSize              int64
+	ExeOwner             string
+	ExeModTime           time.Time
+	RuntimeArchive       string
+	RuntimeArchiveSHA256 string
+	KnownBuild           string
+	CommercialFeatures   []string
+	ApplicationName      string
+	ApplicationVersion   string
+	Component            string
+	ComponentPath        string
+	Settings             map[string]string
+	ConfigSource         string
+	ServiceName          string
+	ServiceState         string
+	RequestedVersion     string
+	RequestedVendor      string
+	MatchingRuntime      string
+	ToolName             string
+	InstallSource        string
+	ProductCode          string
+	ProductName          string
+	Publisher            string
+	InstallDate          string
+	Properties           map[string]string
+	ErrorText            string
 }
 
 func Scan() {
 
 	usageMessage := "Use './java-scanner scan --help' for a list of scanning options!"
-	if !(detectCurrentPath || detectLinuxAlternatives || detectWindowsRegistry || detectRunningProcesses || detectFileSystemScan) {
+	if !isAnyDetectionMethodActivated() {
 		log.Infof("No detected methods configured! " + usageMessage)
 		return
 	}
+	logActivatedDetectionMethods()
+	log.Infof(usageMessage)
+
+	policy, err := loadConfiguredPolicy()
+	if err != nil {
+		log.Fatalf("failed loading policy %s: %s", policyFile, err)
+	}
+
+	overallResult := runDetectors()
+	logOverallResults(overallResult)
+	createCsvFile(overallResult)
+
+	if appendToFindingsJson {
+		addInfoToFindingsJson(overallResult)
+	}
+	if pushToUrl != "" {
+		if err := pushFindings(pushToUrl, overallResult); err != nil {
+			log.Errorf("Failed to push findings to %s: %s", pushToUrl, err)
+		}
+	}
+	if policy != nil {
+		exitOnViolations(policy, overallResult)
+	}
+
+}
 
+func isAnyDetectionMethodActivated() bool {
+	return detectCurrentPath || detectLinuxAlternatives || detectWindowsRegistry || detectRunningProcesses || detectFileSystemScan || detectDesktopComponents || detectEnvironmentConfig || detectServices || detectBuildConfig || detectCiTools || detectWindowsInstallations || detectMacOSJavaVirtualMachines
+}
+
+func logActivatedDetectionMethods() {
 	log.Infof("Activated Detection methods:" +
 		formatMethodIfActivated(detectRunningProcesses, RunningProcesses) +
 		formatMethodIfActivated(detectFileSystemScan, FileSystem) +
 		formatMethodIfActivated(detectLinuxAlternatives, LinuxAlternatives) +
 		formatMethodIfActivated(detectWindowsRegistry, WindowsRegistry) +
-		formatMethodIfActivated(detectCurrentPath, CurrentPath))
-	log.Infof(usageMessage)
+		formatMethodIfActivated(detectWindowsInstallations, WindowsInstallations) +
+		formatMethodIfActivated(detectMacOSJavaVirtualMachines, MacOSJavaVirtualMachines) +
+		formatMethodIfActivated(detectCurrentPath, CurrentPath) +
+		formatMethodIfActivated(detectEnvironmentConfig, EnvironmentConfig) +
+		formatMethodIfActivated(detectServices, Services) +
+		formatMethodIfActivated(detectBuildConfig, BuildConfig) +
+		formatMethodIfActivated(detectCiTools, CiTools) +
+		formatMethodIfActivated(detectDesktopComponents, DesktopComponents))
+}
 
+// notScannedPath is a path a detection method could not or did not scan.
+// They are reported with the overall results, so that auditors know what was not covered.
+type notScannedPath struct {
+	DetectionMethod DetectionMethod
+	Path            string
+	Reason          string
+}
+
+// notScannedPaths of the latest run of the detectors
+var notScannedPaths []notScannedPath
+
+// runDetectors runs all activated detection methods and returns their classified findings.
+func runDetectors() []JavaInfo {
 	overallResult := []JavaInfo{}
+	notScannedPaths = nil
 
 	if detectRunningProcesses {
 		resultRunningProcesses := detectRunningProcessesMain()
@@ -97,18 +226,69 @@ func Scan() {
 		fmt.Println()
 	}
 
+	if detectWindowsInstallations {
+		resultWindowsInstallations := detectWindowsInstallationsMain()
+		overallResult = append(overallResult, resultWindowsInstallations...)
+		fmt.Println()
+	}
+
+	if detectMacOSJavaVirtualMachines {
+		resultMacOSJavaVirtualMachines := detectMacOSJavaVirtualMachinesMain()
+		overallResult = append(overallResult, resultMacOSJavaVirtualMachines...)
+		fmt.Println()
+	}
+
 	if detectCurrentPath {
 		resultCurrentPath := detectCurrentPathMain()
 		overallResult = append(overallResult, resultCurrentPath...)
 		fmt.Println()
 	}
-	logOverallResults(overallResult)
-	createCsvFile(overallResult)
-
-	if appendToFindingsJson {
-		addInfoToFindingsJson(overallResult)
+	if detectEnvironmentConfig {
+		resultEnvironmentConfig := detectEnvironmentConfigMain()
+		overallResult = append(overallResult, resultEnvironmentConfig...)
+		fmt.Println()
 	}
-
+	if detectServices {
+		resultServices := detectServicesMain()
+		overallResult = append(overallResult, resultServices...)
+		fmt.Println()
+	}
+	if detectBuildConfig {
+		resultBuildConfig := detectBuildConfigMain()
+		overallResult = append(overallResult, resultBuildConfig...)
+		fmt.Println()
+	}
+	if detectCiTools {
+		resultCiTools := detectCiToolsMain()
+		overallResult = append(overallResult, resultCiTools...)
+		fmt.Println()
+	}
+	if detectDesktopComponents {
+		resultDesktopComponents := detectDesktopComponentsMain(overallResult)
+		overallResult = append(overallResult, resultDesktopComponents...)
+		fmt.Println()
+	}
+	if fingerprint || knownBuildsFile != "" {
+		fingerprintFindings(overallResult)
+	}
+	for i := range overallResult {
+		info := &overallResult[i]
+		if info.JvmImplementation == JvmUnknown {
+			info.JvmImplementation = classifyJvmImplementation(info)
+		}
+		// the distribution of a known build has already been set by fingerprintFindings
+		if info.Distribution == DistributionUnknown {
+			info.Distribution = classifyDistribution(info)
+		}
+		info.LicenseCategory = classifyLicense(info)
+	}
+	if detectBuildConfig || detectCiTools {
+		correlateRequestedJdks(overallResult)
+	}
+	if detectCiTools {
+		correlateCiTools(overallResult)
+	}
+	return overallResult
 }
 
 func isUnrecognizedOption(out []byte) bool {
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+var shellAssignment = regexp.MustCompile(`^(?:export\s+|declare\s+-x\s+|readonly\s+)?([A-Za-z_][A-Za-z0-9_]*)=(.*)$`)
+var shellVariable = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)(?::?-([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// evaluateShellAssignments evaluates the variable assignments of a shell script, an environment file or a systemd
+// Environment= line without running it. Variables are expanded with the values assigned before or given in environment,
+// which is updated. Assignments, that cannot be evaluated statically, e.g. command substitutions, are ignored.
+// The names of the variables assigned are returned in the order of assignment.
+func evaluateShellAssignments(content string, environment map[string]string) []string {
+	var assigned []string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		submatch := shellAssignment.FindStringSubmatch(line)
+		if submatch == nil {
+			continue
+		}
+		value, ok := evaluateShellWord(submatch[2], environment)
+		if !ok {
+			continue
+		}
+		environment[submatch[1]] = value
+		assigned = append(assigned, submatch[1])
+	}
+	return assigned
+}
+
+// evaluateShellWord removes the quotes of a word and expands its variables.
+// Text after the word, like a trailing comment or a following command, is ignored.
+func evaluateShellWord(word string, environment map[string]string) (string, bool) {
+	var value strings.Builder
+	for i := 0; i < len(word); i++ {
+		switch c := word[i]; c {
+		case '\'':
+			end := strings.IndexByte(word[i+1:], '\'')
+			if end < 0 {
+				return "", false
+			}
+			value.WriteString(word[i+1 : i+1+end])
+			i += end + 1
+		case '"':
+			end := strings.IndexByte(word[i+1:], '"')
+			if end < 0 {
+				return "", false
+			}
+			quoted := word[i+1 : i+1+end]
+			if strings.Contains(quoted, "$(") || strings.Contains(quoted, "`") {
+				return "", false
+			}
+			value.WriteString(expandShellVariables(quoted, environment))
+			i += end + 1
+		case '`':
+			return "", false
+		case '$':
+			if strings.HasPrefix(word[i:], "$(") {
+				return "", false
+			}
+			location := shellVariable.FindStringIndex(word[i:])
+			if location == nil || location[0] != 0 {
+				value.WriteByte(c)
+				continue
+			}
+			value.WriteString(expandShellVariables(word[i:i+location[1]], environment))
+			i += location[1] - 1
+		case ' ', '\t', ';', '&', '|':
+			return value.String(), true
+		case '\\':
+			if i+1 < len(word) {
+				i++
+				value.WriteByte(word[i])
+			}
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return value.String(), true
+}
+
+func expandShellVariables(text string, environment map[string]string) string {
+	return shellVariable.ReplaceAllStringFunc(text, func(variable string) string {
+		submatch := shellVariable.FindStringSubmatch(variable)
+		name := submatch[1] + submatch[3]
+		if value := environment[name]; value != "" {
+			return value
+		}
+		return submatch[2]
+	})
+}
+
+// parseSystemdEnvironment returns the assignments of an Environment= line of a systemd unit,
+// e.g. Environment="JAVA_HOME=/opt/jdk" "CATALINA_HOME=/opt/tomcat", as lines of an environment file.
+func parseSystemdEnvironment(value string) string {
+	var assignments []string
+	for len(value) > 0 {
+		value = strings.TrimLeft(value, " \t")
+		if value == "" {
+			break
+		}
+		var assignment string
+		if value[0] == '"' || value[0] == '\'' {
+			end := strings.IndexByte(value[1:], value[0])
+			if end < 0 {
+				assignment, value = value[1:], ""
+			} else {
+				assignment, value = value[1:1+end], value[end+2:]
+			}
+		} else {
+			assignment, value, _ = strings.Cut(value, " ")
+		}
+		// systemd does not expand variables, the value is taken literally
+		name, literal, found := strings.Cut(assignment, "=")
+		if found {
+			assignments = append(assignments, name+"='"+strings.ReplaceAll(literal, "'", "")+"'")
+		}
+	}
+	return strings.Join(assignments, "\n")
+}
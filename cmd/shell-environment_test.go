@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_evaluateShellAssignments(t *testing.T) {
+	content := `#!/bin/sh
+# comment
+export JAVA_HOME=/opt/jdk-17
+JRE_HOME="${JAVA_HOME}/jre"   # trailing comment
+CATALINA_OPTS='-Xmx1g $NOT_EXPANDED'
+PATH=$JAVA_HOME/bin:$PATH
+JDK_HOME=$(dirname $(readlink -f /usr/bin/javac))
+TOMCAT_USER=${TOMCAT_USER:-tomcat}
+    export LANG=C; echo done
+`
+	environment := map[string]string{}
+	assigned := evaluateShellAssignments(content, environment)
+
+	wantAssigned := []string{"JAVA_HOME", "JRE_HOME", "CATALINA_OPTS", "PATH", "TOMCAT_USER", "LANG"}
+	if !reflect.DeepEqual(assigned, wantAssigned) {
+		t.Errorf("evaluateShellAssignments() assigned %v, want %v", assigned, wantAssigned)
+	}
+	wantEnvironment := map[string]string{
+		"JAVA_HOME":     "/opt/jdk-17",
+		"JRE_HOME":      "/opt/jdk-17/jre",
+		"CATALINA_OPTS": "-Xmx1g $NOT_EXPANDED",
+		"PATH":          "/opt/jdk-17/bin:",
+		"TOMCAT_USER":   "tomcat",
+		"LANG":          "C",
+	}
+	if !reflect.DeepEqual(environment, wantEnvironment) {
+		t.Errorf("evaluateShellAssignments() environment = %v, want %v", environment, wantEnvironment)
+	}
+}
+
+func Test_parseSystemdEnvironment(t *testing.T) {
+	environment := map[string]string{}
+	evaluateShellAssignments(parseSystemdEnvironment(`"JAVA_HOME=/usr/lib/jvm/java 17" CATALINA_BASE=/var/lib/tomcat JAVA_OPTS=$X`), environment)
+	want := map[string]string{"JAVA_HOME": "/usr/lib/jvm/java 17", "CATALINA_BASE": "/var/lib/tomcat", "JAVA_OPTS": "$X"}
+	if !reflect.DeepEqual(environment, want) {
+		t.Errorf("parseSystemdEnvironment() = %v, want %v", environment, want)
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var systemdUnitDirectories = []string{"/etc/systemd/system", "/run/systemd/system", "/usr/lib/systemd/system", "/lib/systemd/system"}
+
+// systemdUnit is a service unit with the contents of its unit file followed by the ones of its drop-ins.
+type systemdUnit struct {
+	Name     string
+	Files    []string
+	Contents map[string]string
+}
+
+// readSystemdServiceUnits reads all service units including their drop-ins.
+// Units in /etc override the ones of the same name installed by packages.
+func readSystemdServiceUnits() []systemdUnit {
+	unitFiles := map[string]string{}
+	for _, directory := range systemdUnitDirectories {
+		files, _ := filepath.Glob(filepath.Join(directory, "*.service"))
+		for _, file := range files {
+			if _, found := unitFiles[filepath.Base(file)]; !found {
+				unitFiles[filepath.Base(file)] = file
+			}
+		}
+	}
+	var units []systemdUnit
+	for _, name := range sortedKeys(unitFiles) {
+		unit := systemdUnit{Name: strings.TrimSuffix(name, ".service"), Contents: map[string]string{}}
+		files := []string{unitFiles[name]}
+		for _, directory := range systemdUnitDirectories {
+			dropIns, _ := filepath.Glob(filepath.Join(directory, name+".d", "*.conf"))
+			files = append(files, dropIns...)
+		}
+		for _, file := range files {
+			if content, err := os.ReadFile(file); err == nil {
+				unit.Files = append(unit.Files, file)
+				unit.Contents[file] = string(content)
+			}
+		}
+		units = append(units, unit)
+	}
+	return units
+}
+
+// settings returns the values of a setting in the order of the unit file and its drop-ins.
+// An empty assignment resets the values assigned before, like for ExecStart= in a drop-in.
+func (unit systemdUnit) settings(name string) []string {
+	var values []string
+	for _, file := range unit.Files {
+		for _, line := range strings.Split(unit.Contents[file], "\n") {
+			key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+			if !found || strings.TrimSpace(key) != name {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			if value == "" {
+				values = nil
+				continue
+			}
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// setting returns the last value of a setting, an empty string if it is not set.
+func (unit systemdUnit) setting(name string) string {
+	values := unit.settings(name)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// isEnabled checks whether the unit is wanted or required by another unit, the result of 'systemctl enable'.
+func (unit systemdUnit) isEnabled() bool {
+	for _, directory := range systemdUnitDirectories {
+		links, _ := filepath.Glob(filepath.Join(directory, "*.wants", unit.Name+".service"))
+		requires, _ := filepath.Glob(filepath.Join(directory, "*.requires", unit.Name+".service"))
+		if len(links) > 0 || len(requires) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// environment evaluates the Environment= and EnvironmentFile= settings in their order.
+// Missing environment files are ignored.
+func (unit systemdUnit) environment() map[string]string {
+	environment := map[string]string{}
+	for _, file := range unit.Files {
+		for _, line := range strings.Split(unit.Contents[file], "\n") {
+			key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+			switch {
+			case !found:
+			case key == "Environment":
+				evaluateShellAssignments(parseSystemdEnvironment(value), environment)
+			case key == "EnvironmentFile":
+				if content, err := os.ReadFile(strings.TrimPrefix(value, "-")); err == nil {
+					evaluateShellAssignments(string(content), environment)
+				}
+			}
+		}
+	}
+	return environment
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// systemdFixture writes the unit files below the root directory and uses its etc and usr/lib unit directories.
+func systemdFixture(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	writeFixtureFiles(t, root, files)
+	previous := systemdUnitDirectories
+	t.Cleanup(func() { systemdUnitDirectories = previous })
+	systemdUnitDirectories = []string{filepath.Join(root, "etc", "systemd", "system"), filepath.Join(root, "usr", "lib", "systemd", "system")}
+	return root
+}
+
+func Test_readSystemdServiceUnits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd services do not exist on windows")
+	}
+	root := systemdFixture(t, map[string]string{
+		"usr/lib/systemd/system/app.service":                "[Service]\nUser=app\nExecStart=/opt/app/bin/app\nExecStart=/opt/app/bin/app --second\n",
+		"etc/systemd/system/app.service.d/10-reset.conf":    "[Service]\nExecStart=\nExecStart=/opt/jdk-17/bin/java -jar /opt/app/app.jar\n",
+		"usr/lib/systemd/system/app.service.d/20-user.conf": "[Service]\nUser = tomcat\n",
+		"usr/lib/systemd/system/kafka.service":              "[Service]\nExecStart=/opt/kafka/bin/kafka-server-start.sh\n",
+		"etc/systemd/system/kafka.service":                  "[Service]\nUser=kafka\nExecStart=/opt/kafka/bin/start.sh\n",
+	})
+
+	units := readSystemdServiceUnits()
+	if len(units) != 2 || units[0].Name != "app" || units[1].Name != "kafka" {
+		t.Fatalf("readSystemdServiceUnits() = %+v, want the units app and kafka", units)
+	}
+	app, kafka := units[0], units[1]
+	wantFiles := []string{
+		filepath.Join(root, "usr", "lib", "systemd", "system", "app.service"),
+		filepath.Join(root, "etc", "systemd", "system", "app.service.d", "10-reset.conf"),
+		filepath.Join(root, "usr", "lib", "systemd", "system", "app.service.d", "20-user.conf"),
+	}
+	if !reflect.DeepEqual(app.Files, wantFiles) {
+		t.Errorf("readSystemdServiceUnits() files = %v, want %v", app.Files, wantFiles)
+	}
+	// the empty assignment of the drop-in resets the values of the unit file
+	if got := app.settings("ExecStart"); !reflect.DeepEqual(got, []string{"/opt/jdk-17/bin/java -jar /opt/app/app.jar"}) {
+		t.Errorf("settings(ExecStart) = %v, want the one of the drop-in", got)
+	}
+	if got := app.setting("User"); got != "tomcat" {
+		t.Errorf("setting(User) = %v, want tomcat", got)
+	}
+	if got := app.setting("Group"); got != "" {
+		t.Errorf("setting(Group) = %v, want an empty string", got)
+	}
+	// the unit file in /etc replaces the one installed by the package
+	if got := kafka.settings("ExecStart"); len(kafka.Files) != 1 || !reflect.DeepEqual(got, []string{"/opt/kafka/bin/start.sh"}) {
+		t.Errorf("settings(ExecStart) = %v of %v, want the one of the unit file in /etc", got, kafka.Files)
+	}
+}
+
+func Test_systemdUnitEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd services do not exist on windows")
+	}
+	root := t.TempDir()
+	writeFixtureFiles(t, root, map[string]string{"etc/default/app": "# defaults\nJAVA_HOME=/opt/jdk-11\nJAVA_OPTS=\"-Xmx1g\"\n"})
+	unitFile, dropIn := filepath.Join(root, "app.service"), filepath.Join(root, "app.service.d", "override.conf")
+	unit := systemdUnit{Name: "app", Files: []string{unitFile, dropIn}, Contents: map[string]string{
+		unitFile: "[Service]\nEnvironment=\"APP_HOME=/opt/app\" LANG=C\n" +
+			"EnvironmentFile=-" + filepath.Join(root, "etc", "default", "app") + "\n" +
+			"EnvironmentFile=-" + filepath.Join(root, "etc", "default", "missing") + "\n",
+		dropIn: "[Service]\nEnvironment=JAVA_HOME=/opt/jdk-17\nEnvironment=\"CONFIG=${APP_HOME}/conf\"\n",
+	}}
+
+	// systemd does not expand variables in Environment= assignments
+	want := map[string]string{"APP_HOME": "/opt/app", "LANG": "C", "JAVA_HOME": "/opt/jdk-17", "JAVA_OPTS": "-Xmx1g", "CONFIG": "${APP_HOME}/conf"}
+	if got := unit.environment(); !reflect.DeepEqual(got, want) {
+		t.Errorf("environment() = %v, want %v", got, want)
+	}
+}
+
+func Test_findJavaSystemdServices(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd services do not exist on windows")
+	}
+	scripts := t.TempDir()
+	writeFixtureFiles(t, scripts, map[string]string{
+		"app.sh":    "#!/bin/sh\nexec \"$JAVA_HOME/bin/java\" -jar /opt/app/app.jar\n",
+		"backup.sh": "#!/bin/sh\nexec rsync -a /srv/ /backup/\n",
+	})
+	root := systemdFixture(t, map[string]string{
+		"usr/lib/systemd/system/java-scanner-test-app.service": "[Service]\nEnvironment=JAVA_HOME=/opt/jdk-17\n" +
+			"ExecStart=" + filepath.Join(scripts, "app.sh") + "\n",
+		// the java home in the environment does not make the backup a JVM
+		"usr/lib/systemd/system/java-scanner-test-backup.service": "[Service]\nEnvironment=JAVA_HOME=/opt/jdk-17\n" +
+			"ExecStart=" + filepath.Join(scripts, "backup.sh") + "\n",
+		"usr/lib/systemd/system/java-scanner-test-kafka.service":                     "[Service]\nUser=kafka\nExecStart=/opt/jdk-21/bin/java -cp /opt/kafka/libs/* kafka.Kafka\n",
+		"usr/lib/systemd/system/java-scanner-test-sshd.service":                      "[Service]\nExecStart=/usr/sbin/sshd -D\n",
+		"etc/systemd/system/multi-user.target.wants/java-scanner-test-kafka.service": "",
+	})
+
+	services, names := findJavaSystemdServices()
+	want := []javaService{
+		{Name: "java-scanner-test-app", State: "disabled/unknown", Username: "root",
+			Source: filepath.Join(root, "usr", "lib", "systemd", "system", "java-scanner-test-app.service"), JavaBinary: "/opt/jdk-17/bin/java"},
+		{Name: "java-scanner-test-kafka", State: "enabled/unknown", Username: "kafka",
+			Source: filepath.Join(root, "usr", "lib", "systemd", "system", "java-scanner-test-kafka.service"), JavaBinary: "/opt/jdk-21/bin/java"},
+	}
+	if !reflect.DeepEqual(services, want) {
+		t.Errorf("findJavaSystemdServices() = %+v, want %+v", services, want)
+	}
+	if len(names) != 4 || !names["java-scanner-test-sshd"] || !names["java-scanner-test-backup"] {
+		t.Errorf("findJavaSystemdServices() names = %v, want all four units", names)
+	}
+}
+
+func Test_isEnabled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd services do not exist on windows")
+	}
+	root := systemdFixture(t, map[string]string{"usr/lib/systemd/system/app.service": "[Service]\n"})
+	unit := systemdUnit{Name: "app"}
+	if unit.isEnabled() {
+		t.Errorf("isEnabled() = true, want false without link")
+	}
+	writeFixtureFiles(t, root, map[string]string{"etc/systemd/system/network-online.target.requires/app.service": ""})
+	if !unit.isEnabled() {
+		t.Errorf("isEnabled() = false, want true with a link in a requires directory")
+	}
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var detectCurrentPathAllUsers bool
+
+const passwdFile = "/etc/passwd"
+
+var loginDefsFile = "/etc/login.defs"
+
+// systemProfileDirectory contains the system wide profiles of the login shells.
+var systemProfileDirectory = "/etc"
+
+// loginUser is a local user, that can log in.
+type loginUser struct {
+	Name  string
+	Uid   string
+	Home  string
+	Shell string
+}
+
+// readLoginUsers returns the users of the passwd file with a login shell.
+func readLoginUsers(content string) []loginUser {
+	var users []loginUser
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ":")
+		if len(fields) < 7 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		shell := filepath.Base(fields[6])
+		if fields[6] == "" || shell == "nologin" || shell == "false" || shell == "sync" || shell == "shutdown" || shell == "halt" {
+			continue
+		}
+		users = append(users, loginUser{Name: fields[0], Uid: fields[2], Home: fields[5], Shell: fields[6]})
+	}
+	return users
+}
+
+// profileFilesOf returns the files a login shell of the user evaluates in their order. The files are evaluated
+// statically, so the files sourced by them are listed as well: /etc/profile sources /etc/profile.d/*.sh, other files
+// are only listed if the file sourcing them refers to them, like ~/.profile sourcing ~/.bashrc.
+func profileFilesOf(user loginUser) []string {
+	system := func(name string) string { return filepath.Join(systemProfileDirectory, filepath.FromSlash(name)) }
+	home := func(name string) string { return filepath.Join(user.Home, name) }
+	files := []string{system("environment")}
+	profile := func() {
+		files = append(files, system("profile"))
+		// Debian sources /etc/bash.bashrc in /etc/profile for bash
+		if filepath.Base(user.Shell) == "bash" && refersTo(system("profile"), "bash.bashrc") {
+			files = append(files, system("bash.bashrc"))
+		}
+		profileScripts, _ := filepath.Glob(system("profile.d/*.sh"))
+		files = append(files, profileScripts...)
+	}
+	switch filepath.Base(user.Shell) {
+	case "bash":
+		profile()
+		// of ~/.bash_profile, ~/.bash_login and ~/.profile bash only reads the first existing one
+		for _, name := range []string{".bash_profile", ".bash_login", ".profile"} {
+			if _, err := os.Stat(home(name)); err == nil {
+				files = append(files, home(name))
+				if refersTo(home(name), ".bashrc") {
+					files = append(files, home(".bashrc"))
+					// RedHat sources /etc/bashrc in ~/.bashrc
+					if refersTo(home(".bashrc"), "/etc/bashrc") {
+						files = append(files, system("bashrc"))
+					}
+				}
+				break
+			}
+		}
+	case "zsh":
+		// Debian places the system wide files in /etc/zsh, RedHat and macOS in /etc
+		for _, name := range []string{"zshenv", "zprofile", "zshrc", "zlogin"} {
+			for _, systemFile := range []string{system("zsh/" + name), system(name)} {
+				if _, err := os.Stat(systemFile); err == nil {
+					files = append(files, systemFile)
+					// e.g. "emulate sh -c 'source /etc/profile'" in /etc/zprofile
+					if name == "zprofile" && refersTo(systemFile, "/etc/profile") {
+						profile()
+					}
+				}
+			}
+			files = append(files, home("."+name))
+		}
+	default:
+		profile()
+		files = append(files, home(".profile"))
+	}
+	return files
+}
+
+// refersTo checks whether the shell script refers to the file, e.g. to source it.
+func refersTo(script string, file string) bool {
+	content, err := os.ReadFile(script)
+	return err == nil && strings.Contains(string(content), file)
+}
+
+// defaultLoginPath returns the PATH login sets before any profile is evaluated.
+func defaultLoginPath(user loginUser) string {
+	variable := "ENV_PATH"
+	path := "/usr/local/bin:/usr/bin:/bin"
+	if user.Uid == "0" {
+		variable = "ENV_SUPATH"
+		path = defaultServicePath
+	}
+	content, _ := os.ReadFile(loginDefsFile)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == variable {
+			path = strings.TrimPrefix(fields[1], "PATH=")
+		}
+	}
+	return path
+}
+
+// resolveUserPath evaluates the PATH assignments of the profiles of the user statically
+// and returns the effective PATH and the file, that set it last.
+func resolveUserPath(user loginUser) (path string, source string) {
+	environment := map[string]string{"HOME": user.Home, "USER": user.Name, "LOGNAME": user.Name, "SHELL": user.Shell,
+		"PATH": defaultLoginPath(user)}
+	source = loginDefsFile
+	for _, file := range profileFilesOf(user) {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, variable := range evaluateShellAssignments(string(content), environment) {
+			if variable == "PATH" {
+				source = file
+			}
+		}
+	}
+	return environment["PATH"], source
+}
+
+// detectCurrentPathOfUsers resolves the java binary on the PATH of each local user with a login shell.
+func detectCurrentPathOfUsers(template JavaInfo) []JavaInfo {
+	var result []JavaInfo
+	content, err := os.ReadFile(passwdFile)
+	if err != nil {
+		log.Warnf("Cannot read local users from %s: %s", passwdFile, err)
+		return result
+	}
+	analyzed := map[string]JavaInfo{}
+	for _, user := range readLoginUsers(string(content)) {
+		path, source := resolveUserPath(user)
+		javaBinary := lookPathIn(path)
+		if javaBinary == "" {
+			log.Debugf("No java binary on PATH %s of user %s", path, user.Name)
+			continue
+		}
+		info := analyzeJavaBinaryOnce(analyzed, javaBinary)
+		info.DetectionMethod = template.DetectionMethod
+		info.ScanTimestamp = template.ScanTimestamp
+		info.Hostname = template.Hostname
+		info.Username = user.Name
+		info.ConfigSource = source + ":PATH"
+		log.Infof("Found java executable %s in path of user %s", javaBinary, user.Name)
+		result = append(result, info)
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func Test_readLoginUsers(t *testing.T) {
+	content := `root:x:0:0:root:/root:/bin/bash
+daemon:x:1:1:daemon:/usr/sbin:/usr/sbin/nologin
+sync:x:4:65534:sync:/bin:/bin/sync
+tomcat:x:998:998::/opt/tomcat:/bin/false
+# comment
+alice:x:1000:1000:Alice,,,:/home/alice:/usr/bin/zsh
+`
+	want := []loginUser{
+		{Name: "root", Uid: "0", Home: "/root", Shell: "/bin/bash"},
+		{Name: "alice", Uid: "1000", Home: "/home/alice", Shell: "/usr/bin/zsh"},
+	}
+	if got := readLoginUsers(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("readLoginUsers() = %v, want %v", got, want)
+	}
+}
+
+// profileFixture writes the files below the root directory and uses its etc directory for the system wide profiles.
+func profileFixture(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	writeFixtureFiles(t, root, files)
+	previousDirectory, previousLoginDefs := systemProfileDirectory, loginDefsFile
+	t.Cleanup(func() { systemProfileDirectory, loginDefsFile = previousDirectory, previousLoginDefs })
+	systemProfileDirectory = filepath.Join(root, "etc")
+	loginDefsFile = filepath.Join(root, "etc", "login.defs")
+	return root
+}
+
+func Test_profileFilesOf(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("login shells are not evaluated on windows")
+	}
+	tests := []struct {
+		name  string
+		shell string
+		files map[string]string
+		want  []string
+	}{
+		{"bash on debian", "/bin/bash", map[string]string{
+			"etc/profile":          "if [ \"$BASH\" ]; then . /etc/bash.bashrc; fi\nfor i in /etc/profile.d/*.sh; do . $i; done\n",
+			"etc/profile.d/jdk.sh": "",
+			"home/alice/.profile":  "if [ -f \"$HOME/.bashrc\" ]; then . \"$HOME/.bashrc\"; fi\n",
+			"home/alice/.bashrc":   "",
+		}, []string{"etc/environment", "etc/profile", "etc/bash.bashrc", "etc/profile.d/jdk.sh", "home/alice/.profile", "home/alice/.bashrc"}},
+		{"bash on redhat", "/bin/bash", map[string]string{
+			"etc/profile":              "",
+			"etc/bash.bashrc":          "",
+			"home/alice/.bash_profile": "[ -f ~/.bashrc ] && . ~/.bashrc\n",
+			"home/alice/.profile":      "",
+			"home/alice/.bashrc":       "if [ -f /etc/bashrc ]; then . /etc/bashrc; fi\n",
+		}, []string{"etc/environment", "etc/profile", "home/alice/.bash_profile", "home/alice/.bashrc", "etc/bashrc"}},
+		{"bash without sourcing bashrc", "/usr/bin/bash", map[string]string{
+			"home/alice/.bash_login": "export PATH=$HOME/bin:$PATH\n",
+			"home/alice/.bashrc":     "",
+		}, []string{"etc/environment", "etc/profile", "home/alice/.bash_login"}},
+		{"zsh on debian", "/usr/bin/zsh", map[string]string{
+			"etc/profile":          "",
+			"etc/profile.d/jdk.sh": "",
+			"etc/zsh/zshenv":       "",
+			"etc/zsh/zprofile":     "# /etc/zsh/zprofile: system-wide .zprofile file for zsh(1).\n",
+		}, []string{"etc/environment", "etc/zsh/zshenv", "home/alice/.zshenv", "etc/zsh/zprofile", "home/alice/.zprofile",
+			"home/alice/.zshrc", "home/alice/.zlogin"}},
+		{"zsh sourcing /etc/profile", "/bin/zsh", map[string]string{
+			"etc/profile.d/jdk.sh": "",
+			"etc/zprofile":         "emulate sh -c 'source /etc/profile'\n",
+			"etc/zshrc":            "",
+		}, []string{"etc/environment", "home/alice/.zshenv", "etc/zprofile", "etc/profile", "etc/profile.d/jdk.sh",
+			"home/alice/.zprofile", "etc/zshrc", "home/alice/.zshrc", "home/alice/.zlogin"}},
+		{"sh", "/bin/sh", map[string]string{"etc/profile.d/jdk.sh": ""},
+			[]string{"etc/environment", "etc/profile", "etc/profile.d/jdk.sh", "home/alice/.profile"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := profileFixture(t, tt.files)
+			var want []string
+			for _, file := range tt.want {
+				want = append(want, filepath.Join(root, filepath.FromSlash(file)))
+			}
+			user := loginUser{Name: "alice", Uid: "1000", Home: filepath.Join(root, "home", "alice"), Shell: tt.shell}
+			if got := profileFilesOf(user); !reflect.DeepEqual(got, want) {
+				t.Errorf("profileFilesOf() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_defaultLoginPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("login shells are not evaluated on windows")
+	}
+	alice, root := loginUser{Name: "alice", Uid: "1000"}, loginUser{Name: "root", Uid: "0"}
+	profileFixture(t, map[string]string{})
+	if got := defaultLoginPath(alice); got != "/usr/local/bin:/usr/bin:/bin" {
+		t.Errorf("defaultLoginPath() = %v without login.defs, want the default of login", got)
+	}
+	if got := defaultLoginPath(root); got != defaultServicePath {
+		t.Errorf("defaultLoginPath() = %v of root without login.defs, want %v", got, defaultServicePath)
+	}
+
+	profileFixture(t, map[string]string{"etc/login.defs": "# comment\nENV_SUPATH\tPATH=/usr/sbin:/usr/bin\nENV_PATH PATH=/usr/bin:/opt/bin\n"})
+	if got := defaultLoginPath(alice); got != "/usr/bin:/opt/bin" {
+		t.Errorf("defaultLoginPath() = %v, want ENV_PATH of login.defs", got)
+	}
+	if got := defaultLoginPath(root); got != "/usr/sbin:/usr/bin" {
+		t.Errorf("defaultLoginPath() = %v of root, want ENV_SUPATH of login.defs", got)
+	}
+}
+
+func Test_resolveUserPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("login shells are not evaluated on windows")
+	}
+	root := profileFixture(t, map[string]string{
+		"etc/login.defs":           "ENV_PATH PATH=/usr/bin:/bin\n",
+		"etc/profile":              "PATH=\"/usr/local/bin:$PATH\"\nexport PATH\n",
+		"etc/profile.d/jdk.sh":     "export JAVA_HOME=/opt/jdk-17\nexport PATH=$JAVA_HOME/bin:$PATH\n",
+		"etc/zsh/zshenv":           "",
+		"home/alice/.profile":      "export PATH=\"$HOME/bin:$PATH\"\n",
+		"home/bob/.zshrc":          "export PATH=/opt/jdk-21/bin:$PATH\n",
+		"home/carol/.bash_profile": "export EDITOR=vi\n",
+	})
+	home := func(name string) string { return filepath.Join(root, "home", name) }
+
+	tests := []struct {
+		user       loginUser
+		wantPath   string
+		wantSource string
+	}{
+		{loginUser{Name: "alice", Uid: "1000", Home: home("alice"), Shell: "/bin/bash"},
+			home("alice") + "/bin:/opt/jdk-17/bin:/usr/local/bin:/usr/bin:/bin", filepath.Join(home("alice"), ".profile")},
+		// zsh does not read /etc/profile and /etc/profile.d
+		{loginUser{Name: "bob", Uid: "1001", Home: home("bob"), Shell: "/usr/bin/zsh"},
+			"/opt/jdk-21/bin:/usr/bin:/bin", filepath.Join(home("bob"), ".zshrc")},
+		{loginUser{Name: "carol", Uid: "1002", Home: home("carol"), Shell: "/bin/bash"},
+			"/opt/jdk-17/bin:/usr/local/bin:/usr/bin:/bin", filepath.Join(root, "etc", "profile.d", "jdk.sh")},
+		{loginUser{Name: "dave", Uid: "1003", Home: home("dave"), Shell: "/usr/bin/zsh"},
+			"/usr/bin:/bin", loginDefsFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.user.Name, func(t *testing.T) {
+			path, source := resolveUserPath(tt.user)
+			if path != tt.wantPath || source != tt.wantSource {
+				t.Errorf("resolveUserPath() = (%v, %v), want (%v, %v)", path, source, tt.wantPath, tt.wantSource)
+			}
+		})
+	}
+}
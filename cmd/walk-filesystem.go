@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// walkError is an error, that occurred while walking a file or directory. The walk continues after it.
+type walkError struct {
+	Path string
+	Err  error
+}
+
+// skippedPath is a directory, that has not been walked on purpose, e.g. a network mount.
+type skippedPath struct {
+	Path   string
+	Reason string
+}
+
+type fileSystemWalkerOptions struct {
+	ExcludePaths   []string
+	ExcludeGlobs   []string
+	IncludeGlobs   []string
+	OneFileSystem  bool
+	MaxDepth       int
+	SkipMountTypes []string
+	FollowSymlinks bool
+}
+
+// fileSystemWalker walks directory trees and skips excluded files and prunes excluded directories.
+type fileSystemWalker struct {
+	options      fileSystemWalkerOptions
+	excludePaths *regexp.Regexp
+	excludeGlobs []*globPattern
+	includeGlobs []*globPattern
+	skipMounts   map[string]string
+	rootDevice   uint64
+	visitedDirs  map[fileIdentity]bool
+	errors       []walkError
+	skipped      []skippedPath
+}
+
+// newFileSystemWalker compiles the regular expressions and gitignore-style glob patterns once for the whole walk.
+// Regular expressions are matched against the full path.
+// Include globs restrict the files visited, directories are always descended into unless excluded.
+func newFileSystemWalker(options fileSystemWalkerOptions) (*fileSystemWalker, error) {
+	walker := &fileSystemWalker{options: options, skipMounts: map[string]string{}}
+	if len(options.ExcludePaths) > 0 {
+		var err error
+		walker.excludePaths, err = regexp.Compile(strings.Join(options.ExcludePaths, "|"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, pattern := range options.ExcludeGlobs {
+		glob, err := compileGlob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		walker.excludeGlobs = append(walker.excludeGlobs, glob)
+	}
+	for _, pattern := range options.IncludeGlobs {
+		glob, err := compileGlob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		walker.includeGlobs = append(walker.includeGlobs, glob)
+	}
+	if len(options.SkipMountTypes) > 0 {
+		for _, mount := range readMountPoints() {
+			if containsFold(options.SkipMountTypes, mount.FSType) {
+				walker.skipMounts[mount.Path] = mount.FSType
+			}
+		}
+	}
+	return walker, nil
+}
+
+// walk calls visit for every file below the root path, that is not excluded.
+// Errors are collected per file or directory instead of aborting the walk.
+func (walker *fileSystemWalker) walk(root string, visit func(path string, entry fs.DirEntry)) {
+	walker.visitedDirs = map[fileIdentity]bool{}
+	if walker.options.OneFileSystem {
+		info, err := os.Stat(root)
+		if err != nil {
+			walker.errors = append(walker.errors, walkError{Path: root, Err: err})
+			return
+		}
+		identity, ok := getFileIdentity(info)
+		if !ok {
+			log.Warnf("Cannot determine the file system of %s, scanning other file systems as well", root)
+		}
+		walker.rootDevice = identity.Device
+	}
+	// like 'find -H', a symbolic link given as root path is followed in any case
+	treeRoot := root
+	if info, err := os.Lstat(root); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		if target, err := filepath.EvalSymlinks(root); err == nil {
+			treeRoot = target
+		}
+	}
+	walker.walkTree(root, treeRoot, root, visit)
+}
+
+// walkTree walks the directory tree at treeRoot, which is reported as treePath.
+// Both differ when walking the target of a followed symbolic link.
+func (walker *fileSystemWalker) walkTree(root string, treeRoot string, treePath string, visit func(path string, entry fs.DirEntry)) {
+	_ = filepath.WalkDir(treeRoot, func(walkedPath string, entry fs.DirEntry, err error) error {
+		path := treePath + strings.TrimPrefix(walkedPath, treeRoot)
+		if err != nil {
+			walker.errors = append(walker.errors, walkError{Path: path, Err: err})
+			return nil
+		}
+		relativePath, _ := filepath.Rel(root, path)
+		relativePath = filepath.ToSlash(relativePath)
+		if walker.isExcluded(path, relativePath, entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return walker.enterDirectory(path, walkedPath, relativePath, entry)
+		}
+		if entry.Type()&fs.ModeSymlink != 0 && walker.options.FollowSymlinks {
+			if walker.followSymlink(root, path, walkedPath, relativePath, visit) {
+				return nil
+			}
+		}
+		if len(walker.includeGlobs) > 0 && !walker.isIncluded(relativePath) {
+			return nil
+		}
+		visit(path, entry)
+		return nil
+	})
+}
+
+// enterDirectory checks the guards against walking other or special file systems, too deep and in circles.
+func (walker *fileSystemWalker) enterDirectory(path string, walkedPath string, relativePath string, entry fs.DirEntry) error {
+	// a root, that was requested explicitly, is walked even if it is a mount of a skipped file system type
+	if len(walker.skipMounts) > 0 && relativePath != "." {
+		absolutePath, _ := filepath.Abs(walkedPath)
+		if fsType, isSkipped := walker.skipMounts[absolutePath]; isSkipped {
+			walker.skipped = append(walker.skipped, skippedPath{Path: path, Reason: "mount of file system type " + fsType})
+			return filepath.SkipDir
+		}
+	}
+	if walker.options.MaxDepth > 0 && relativePath != "." && strings.Count(relativePath, "/")+1 >= walker.options.MaxDepth {
+		return filepath.SkipDir
+	}
+	if !walker.options.OneFileSystem && !walker.options.FollowSymlinks {
+		return nil
+	}
+	info, err := entry.Info()
+	if err != nil {
+		walker.errors = append(walker.errors, walkError{Path: path, Err: err})
+		return filepath.SkipDir
+	}
+	identity, ok := getFileIdentity(info)
+	if !ok {
+		return nil
+	}
+	if walker.options.OneFileSystem && identity.Device != walker.rootDevice {
+		walker.skipped = append(walker.skipped, skippedPath{Path: path, Reason: "mount of another file system"})
+		return filepath.SkipDir
+	}
+	if walker.visitedDirs[identity] {
+		return filepath.SkipDir
+	}
+	walker.visitedDirs[identity] = true
+	return nil
+}
+
+// followSymlink walks the directory a symbolic link points to and reports whether it is a directory.
+// Directories already walked are not walked again, which also prevents symbolic link loops.
+func (walker *fileSystemWalker) followSymlink(root string, path string, walkedPath string, relativePath string, visit func(path string, entry fs.DirEntry)) bool {
+	info, err := os.Stat(walkedPath)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	if walker.options.MaxDepth > 0 && strings.Count(relativePath, "/")+1 >= walker.options.MaxDepth {
+		return true
+	}
+	if identity, ok := getFileIdentity(info); ok && walker.visitedDirs[identity] {
+		log.Debugf("Not following symbolic link %s to an already scanned directory", path)
+		return true
+	}
+	target, err := filepath.EvalSymlinks(walkedPath)
+	if err != nil {
+		walker.errors = append(walker.errors, walkError{Path: path, Err: err})
+		return true
+	}
+	walker.walkTree(root, target, path, visit)
+	return true
+}
+
+func (walker *fileSystemWalker) isExcluded(path string, relativePath string, isDir bool) bool {
+	if walker.excludePaths != nil && walker.excludePaths.MatchString(path) {
+		return true
+	}
+	return relativePath != "." && matchesAnyGlob(walker.excludeGlobs, relativePath, isDir)
+}
+
+// isIncluded checks whether the file or one of its parent directories matches an include glob.
+func (walker *fileSystemWalker) isIncluded(relativePath string) bool {
+	if matchesAnyGlob(walker.includeGlobs, relativePath, false) {
+		return true
+	}
+	for parent := relativePath; strings.Contains(parent, "/"); {
+		parent = parent[:strings.LastIndex(parent, "/")]
+		if matchesAnyGlob(walker.includeGlobs, parent, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// globPattern is a gitignore-style pattern:
+// a pattern without slash matches a file or directory name at any depth,
+// otherwise it matches the path relative to the root path.
+// '*' matches within a path element, '**' across path elements and a trailing slash matches directories only.
+type globPattern struct {
+	pattern       *regexp.Regexp
+	nameOnly      bool
+	directoryOnly bool
+}
+
+func compileGlob(glob string) (*globPattern, error) {
+	pattern := &globPattern{}
+	if strings.HasSuffix(glob, "/") {
+		pattern.directoryOnly = true
+		glob = strings.TrimSuffix(glob, "/")
+	}
+	pattern.nameOnly = !strings.Contains(glob, "/")
+	glob = strings.TrimPrefix(glob, "/")
+
+	var expression strings.Builder
+	expression.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**/") {
+				expression.WriteString("(?:.*/)?")
+				i += 2
+			} else if strings.HasPrefix(glob[i:], "**") {
+				expression.WriteString(".*")
+				i++
+			} else {
+				expression.WriteString("[^/]*")
+			}
+		case '?':
+			expression.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				expression.WriteString(regexp.QuoteMeta(string(c)))
+			} else {
+				class := glob[i+1 : i+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				expression.WriteString("[" + class + "]")
+				i += end
+			}
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expression.WriteString("$")
+
+	var err error
+	pattern.pattern, err = regexp.Compile(expression.String())
+	return pattern, err
+}
+
+func (pattern *globPattern) matches(relativePath string, isDir bool) bool {
+	if pattern.directoryOnly && !isDir {
+		return false
+	}
+	if pattern.nameOnly {
+		return pattern.pattern.MatchString(relativePath[strings.LastIndex(relativePath, "/")+1:])
+	}
+	return pattern.pattern.MatchString(relativePath)
+}
+
+func matchesAnyGlob(patterns []*globPattern, relativePath string, isDir bool) bool {
+	for _, pattern := range patterns {
+		if pattern.matches(relativePath, isDir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func Test_globPattern_matches(t *testing.T) {
+	tests := []struct {
+		name         string
+		glob         string
+		relativePath string
+		isDir        bool
+		want         bool
+	}{
+		{"name at any depth", "*.bak", "a/b/java.bak", false, true},
+		{"name not matching", "*.bak", "a/b/java", false, false},
+		{"directory only on file", "cache/", "a/cache", false, false},
+		{"directory only on directory", "cache/", "a/cache", true, true},
+		{"anchored", "/opt/jdk*", "opt/jdk-17", true, true},
+		{"anchored not at depth", "opt/jdk*", "x/opt/jdk-17", true, false},
+		{"star within element", "opt/*/bin", "opt/jdk/x/bin", true, false},
+		{"double star across elements", "opt/**/bin", "opt/jdk/x/bin", true, true},
+		{"leading double star", "**/jre/bin", "jdk8/jre/bin", true, true},
+		{"leading double star without prefix", "**/jre/bin", "jre/bin", true, true},
+		{"character class", "jdk-1[17]", "jdk-17", true, true},
+		{"negated character class", "jdk-1[!17]", "jdk-17", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := compileGlob(tt.glob)
+			if err != nil {
+				t.Fatalf("compileGlob() error = %v", err)
+			}
+			if got := pattern.matches(tt.relativePath, tt.isDir); got != tt.want {
+				t.Errorf("matches(%v) = %v, want %v", tt.relativePath, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fileSystemWalker_walk(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{"jdk-17/bin/java", "jdk-17/lib/x.jar", "cache/jdk/bin/java", "old/bin/java"} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	walker, err := newFileSystemWalker(fileSystemWalkerOptions{
+		ExcludePaths: []string{`[/\\]old[/\\]`},
+		ExcludeGlobs: []string{"cache/"},
+		IncludeGlobs: []string{"**/bin/*"},
+	})
+	if err != nil {
+		t.Fatalf("newFileSystemWalker() error = %v", err)
+	}
+	var got []string
+	walker.walk(root, func(path string, entry fs.DirEntry) {
+		relativePath, _ := filepath.Rel(root, path)
+		got = append(got, filepath.ToSlash(relativePath))
+	})
+	walker.walk(filepath.Join(root, "missing"), func(path string, entry fs.DirEntry) {})
+	sort.Strings(got)
+
+	if want := []string{"jdk-17/bin/java"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("walk() visited %v, want %v", got, want)
+	}
+	if len(walker.errors) != 1 {
+		t.Errorf("walk() collected errors %v, want one error for the missing root path", walker.errors)
+	}
+}
+
+func Test_fileSystemWalker_walkGuards(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "opt", "jdk", "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "opt", "jdk", "bin", "java"), []byte{}, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// current -> jdk and a loop back to opt
+	if err := os.Symlink(filepath.Join(root, "opt", "jdk"), filepath.Join(root, "current")); err != nil {
+		t.Skipf("symbolic links not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(root, "opt"), filepath.Join(root, "opt", "jdk", "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		options fileSystemWalkerOptions
+		want    []string
+	}{
+		{"default", fileSystemWalkerOptions{}, []string{"current", "opt/jdk/bin/java", "opt/jdk/loop"}},
+		{"follow symlinks", fileSystemWalkerOptions{FollowSymlinks: true}, []string{"current/bin/java"}},
+		{"max depth", fileSystemWalkerOptions{MaxDepth: 2}, []string{"current"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			walker, err := newFileSystemWalker(tt.options)
+			if err != nil {
+				t.Fatalf("newFileSystemWalker() error = %v", err)
+			}
+			var got []string
+			walker.walk(root, func(path string, entry fs.DirEntry) {
+				relativePath, _ := filepath.Rel(root, path)
+				got = append(got, filepath.ToSlash(relativePath))
+			})
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("walk() visited %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fileSystemWalker_walkSkippedMounts(t *testing.T) {
+	root := t.TempDir()
+	writeFixtureFiles(t, root, map[string]string{
+		"bin/java":           "",
+		"proc/self/exe/java": "",
+	})
+	walker, err := newFileSystemWalker(fileSystemWalkerOptions{})
+	if err != nil {
+		t.Fatalf("newFileSystemWalker() error = %v", err)
+	}
+	absoluteRoot, _ := filepath.Abs(root)
+	// the root itself is on a skipped file system, e.g. a scan of a mounted network share
+	walker.skipMounts = map[string]string{absoluteRoot: "nfs", filepath.Join(absoluteRoot, "proc"): "proc"}
+	var got []string
+	walker.walk(root, func(path string, entry fs.DirEntry) {
+		relativePath, _ := filepath.Rel(root, path)
+		got = append(got, filepath.ToSlash(relativePath))
+	})
+	if want := []string{"bin/java"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("walk() visited %v, want %v", got, want)
+	}
+	if len(walker.skipped) != 1 || walker.skipped[0].Reason != "mount of file system type proc" {
+		t.Errorf("walk() skipped %v, want only the proc mount", walker.skipped)
+	}
+}
+
+func Test_parseMountInfo(t *testing.T) {
+	content := `23 28 0:22 / /proc rw,relatime - proc proc rw
+28 1 259:2 / / rw,relatime shared:1 - ext4 /dev/nvme0n1p2 rw
+91 28 0:45 / /mnt/java\040share rw,relatime shared:40 master:3 - nfs4 filer:/export/java rw,vers=4.1
+`
+	want := []mountPoint{
+		{Path: "/proc", FSType: "proc", Source: "proc"},
+		{Path: "/", FSType: "ext4", Source: "/dev/nvme0n1p2"},
+		{Path: "/mnt/java share", FSType: "nfs4", Source: "filer:/export/java"},
+	}
+	if got := parseMountInfo(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMountInfo() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+import (
+	"log/syslog"
+)
+
+func createSyslogNotifier() (changeNotifier, error) {
+	writer, err := syslog.New(syslog.LOG_WARNING|syslog.LOG_DAEMON, "java-scanner")
+	if err != nil {
+		return nil, err
+	}
+	return func(events []ChangeEvent) error {
+		for _, event := range events {
+			if err := writer.Warning(event.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
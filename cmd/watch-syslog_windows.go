@@ -0,0 +1,11 @@
+//go:build windows
+
+package cmd
+
+import (
+	"errors"
+)
+
+func createSyslogNotifier() (changeNotifier, error) {
+	return nil, errors.New("syslog is not available on windows")
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+var watchInterval time.Duration
+var watchFileSystemEvents bool
+var watchWebhookUrl string
+var watchSyslog bool
+var watchEventsFile string
+
+// webhookClient is the client change events are posted with, a hung webhook must not block the watch loop.
+var webhookClient = &http.Client{Timeout: 30 * time.Second}
+
+// watchDebounce is the time to wait after a file system event before rescanning,
+// so that unpacking a JDK triggers a single scan only.
+const watchDebounce = 10 * time.Second
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "re-run the activated detectors periodically and report changed findings",
+	Run: func(cmd *cobra.Command, args []string) {
+		Watch()
+	},
+}
+
+func init() {
+	addDetectionFlags(watchCmd.Flags())
+	watchCmd.Flags().DurationVarP(&watchInterval, "interval", "i", 10*time.Minute, "interval between two scans")
+	watchCmd.Flags().BoolVarP(&watchFileSystemEvents, "watch-file-system", "w", false, "rescan when files below the file system root paths change")
+	watchCmd.Flags().StringVar(&watchWebhookUrl, "webhook", "", "url change events are posted to as json")
+	watchCmd.Flags().BoolVar(&watchSyslog, "syslog", false, "send change events to the local syslog")
+	watchCmd.Flags().StringVar(&watchEventsFile, "events-file", "", "file change events are appended to as json lines")
+
+	rootCmd.AddCommand(watchCmd)
+}
+
+type ChangeType int64
+
+const (
+	Added ChangeType = iota
+	Removed
+	Changed
+)
+
+func (c ChangeType) String() string {
+	switch c {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+
+	return "unknown"
+}
+
+type ChangeEvent struct {
+	Type      ChangeType
+	Timestamp time.Time
+	Hostname  string
+	Finding   JavaInfo
+	Previous  *JavaInfo `json:",omitempty"`
+}
+
+func (e ChangeEvent) String() string {
+	return fmt.Sprintf("java-scanner: %s %s java %d (build %d) of vendor '%s' at %s, license category %s",
+		e.Type, e.Finding.DetectionMethod, e.Finding.MajorVersion, e.Finding.BuildNumber, e.Finding.Vendor, e.Finding.Exe,
+		e.Finding.LicenseCategory)
+}
+
+func Watch() {
+	if !isAnyDetectionMethodActivated() {
+		log.Fatalf("No detected methods configured! Use './java-scanner watch --help' for a list of scanning options!")
+	}
+	logActivatedDetectionMethods()
+
+	notifiers, err := createChangeNotifiers()
+	if err != nil {
+		log.Fatalf("failed creating change notifiers: %s", err)
+	}
+
+	var fileSystemEvents <-chan fsnotify.Event
+	if watchFileSystemEvents {
+		watcher, err := createFileSystemWatcher(detectFileSystemScanRootPaths)
+		if err != nil {
+			log.Fatalf("failed watching file system root paths: %s", err)
+		}
+		defer watcher.Close()
+		fileSystemEvents = watcher.Events
+	}
+
+	previous := runDetectors()
+	log.Infof("Initial scan found %d findings, watching for changes every %s...", len(previous), watchInterval)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	var debounce <-chan time.Time
+	for {
+		select {
+		case event := <-fileSystemEvents:
+			log.Debugf("File system event: %s", event)
+			debounce = time.After(watchDebounce)
+			continue
+		case <-debounce:
+			log.Infof("Rescanning after file system changes...")
+		case <-ticker.C:
+		}
+		debounce = nil
+
+		current := runDetectors()
+		events := compareFindings(previous, current)
+		log.Infof("Scan found %d findings, %d changes", len(current), len(events))
+		for _, notify := range notifiers {
+			if len(events) == 0 {
+				break
+			}
+			if err := notify(events); err != nil {
+				log.Errorf("Failed to send change events: %s", err)
+			}
+		}
+		previous = current
+	}
+}
+
+// findingKey identifies a finding across scans. Several findings can share a key, e.g. two JVMs of the same
+// binary run by the same user, so findings are compared as multisets of their keys.
+func findingKey(info JavaInfo) string {
+	return info.DetectionMethod.String() + "|" + info.Username + "|" + info.Exe + "|" + info.ComponentPath + "|" + info.ConfigSource + "|" + info.ToolName + "|" + info.RequestedVersion
+}
+
+func compareFindings(previous []JavaInfo, current []JavaInfo) []ChangeEvent {
+	timestamp := time.Now()
+	hostname, _ := os.Hostname()
+	previousByKey := map[string][]JavaInfo{}
+	for _, info := range previous {
+		key := findingKey(info)
+		previousByKey[key] = append(previousByKey[key], info)
+	}
+
+	events := []ChangeEvent{}
+	for _, info := range current {
+		key := findingKey(info)
+		candidates := previousByKey[key]
+		if len(candidates) == 0 {
+			events = append(events, ChangeEvent{Type: Added, Timestamp: timestamp, Hostname: hostname, Finding: info})
+			continue
+		}
+		// prefer an unchanged finding of the same key, so that duplicates do not report changes
+		match := 0
+		for i, candidate := range candidates {
+			if !isFindingChanged(candidate, info) {
+				match = i
+				break
+			}
+		}
+		before := candidates[match]
+		previousByKey[key] = append(candidates[:match:match], candidates[match+1:]...)
+		if isFindingChanged(before, info) {
+			events = append(events, ChangeEvent{Type: Changed, Timestamp: timestamp, Hostname: hostname, Finding: info, Previous: &before})
+		}
+	}
+	for _, info := range previous {
+		key := findingKey(info)
+		if len(previousByKey[key]) > 0 {
+			events = append(events, ChangeEvent{Type: Removed, Timestamp: timestamp, Hostname: hostname, Finding: previousByKey[key][0]})
+			previousByKey[key] = previousByKey[key][1:]
+		}
+	}
+	return events
+}
+
+func isFindingChanged(before JavaInfo, after JavaInfo) bool {
+	return before.Valid != after.Valid ||
+		before.Vendor != after.Vendor ||
+		before.RuntimeName != after.RuntimeName ||
+		before.MajorVersion != after.MajorVersion ||
+		before.BuildNumber != after.BuildNumber ||
+		before.LicenseCategory != after.LicenseCategory
+}
+
+type changeNotifier func(events []ChangeEvent) error
+
+func createChangeNotifiers() ([]changeNotifier, error) {
+	notifiers := []changeNotifier{logChangeEvents}
+	if watchWebhookUrl != "" {
+		notifiers = append(notifiers, postChangeEvents)
+	}
+	if watchEventsFile != "" {
+		notifiers = append(notifiers, appendChangeEventsToFile)
+	}
+	if watchSyslog {
+		notifier, err := createSyslogNotifier()
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	return notifiers, nil
+}
+
+func logChangeEvents(events []ChangeEvent) error {
+	for _, event := range events {
+		log.Warnln(event)
+	}
+	return nil
+}
+
+func postChangeEvents(events []ChangeEvent) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	response, err := webhookClient.Post(watchWebhookUrl, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook %s responded with status %s", watchWebhookUrl, response.Status)
+	}
+	return nil
+}
+
+func appendChangeEventsToFile(events []ChangeEvent) error {
+	eventsFile, err := os.OpenFile(watchEventsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(eventsFile)
+	for _, event := range events {
+		if err = encoder.Encode(event); err != nil {
+			break
+		}
+	}
+	if closeErr := eventsFile.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// createFileSystemWatcher watches the root paths and their direct sub directories.
+// This is sufficient to notice new installations, e.g. a JDK unpacked below /opt.
+func createFileSystemWatcher(rootPaths []string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	for _, rootPath := range rootPaths {
+		if err := watcher.Add(rootPath); err != nil {
+			log.Warnf("Cannot watch root path %s: %s", rootPath, err)
+			continue
+		}
+		entries, err := os.ReadDir(rootPath)
+		if err != nil {
+			log.Warnf("Cannot read root path %s: %s", rootPath, err)
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				if err := watcher.Add(filepath.Join(rootPath, entry.Name())); err != nil {
+					log.Warnf("Cannot watch directory %s: %s", filepath.Join(rootPath, entry.Name()), err)
+				}
+			}
+		}
+	}
+	go func() {
+		for err := range watcher.Errors {
+			log.Warnf("File system watcher error: %s", err)
+		}
+	}()
+	return watcher, nil
+}
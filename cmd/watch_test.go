@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_compareFindings(t *testing.T) {
+	jdk17 := JavaInfo{DetectionMethod: FileSystem, Exe: "/usr/lib/jvm/temurin-17/bin/java", Valid: true, MajorVersion: 17, BuildNumber: 9}
+	jdk17Updated := jdk17
+	jdk17Updated.BuildNumber = 10
+	jdk21 := JavaInfo{DetectionMethod: FileSystem, Exe: "/usr/lib/jvm/temurin-21/bin/java", Valid: true, MajorVersion: 21}
+	jvm := JavaInfo{DetectionMethod: RunningProcesses, Username: "tomcat", Exe: "/usr/lib/jvm/temurin-17/bin/java", Valid: true, MajorVersion: 17}
+
+	tests := []struct {
+		name     string
+		previous []JavaInfo
+		current  []JavaInfo
+		want     []ChangeType
+	}{
+		{"unchanged", []JavaInfo{jdk17, jdk21}, []JavaInfo{jdk21, jdk17}, []ChangeType{}},
+		{"added", []JavaInfo{jdk17}, []JavaInfo{jdk17, jdk21}, []ChangeType{Added}},
+		{"removed", []JavaInfo{jdk17, jdk21}, []JavaInfo{jdk17}, []ChangeType{Removed}},
+		{"changed", []JavaInfo{jdk17}, []JavaInfo{jdk17Updated}, []ChangeType{Changed}},
+		{"duplicate keys unchanged", []JavaInfo{jvm, jvm}, []JavaInfo{jvm, jvm}, []ChangeType{}},
+		{"duplicate key added", []JavaInfo{jvm}, []JavaInfo{jvm, jvm}, []ChangeType{Added}},
+		{"duplicate key removed", []JavaInfo{jvm, jvm, jvm}, []JavaInfo{jvm}, []ChangeType{Removed, Removed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []ChangeType{}
+			for _, event := range compareFindings(tt.previous, tt.current) {
+				got = append(got, event.Type)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("compareFindings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findingKey(t *testing.T) {
+	user := JavaInfo{DetectionMethod: RunningProcesses, Username: "tomcat", Exe: "/usr/bin/java"}
+	other := user
+	other.Username = "jenkins"
+	if findingKey(user) == findingKey(other) {
+		t.Errorf("findings of different users share the key %s", findingKey(user))
+	}
+	restarted := user
+	restarted.ScanTimestamp = restarted.ScanTimestamp.AddDate(0, 0, 1)
+	if findingKey(user) != findingKey(restarted) {
+		t.Errorf("findings of different scans have different keys")
+	}
+}
+
+func Test_postChangeEventsTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	defer func(client *http.Client, url string) { webhookClient, watchWebhookUrl = client, url }(webhookClient, watchWebhookUrl)
+	webhookClient = &http.Client{Timeout: 100 * time.Millisecond}
+	watchWebhookUrl = server.URL
+
+	started := time.Now()
+	if err := postChangeEvents([]ChangeEvent{{}}); err == nil || time.Since(started) > 5*time.Second {
+		t.Errorf("postChangeEvents() to a hung webhook = %v after %s, want a timeout", err, time.Since(started))
+	}
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var detectWindowsInstallations bool
+
+// uninstallKeys are the keys of the programs listed in "Programs and Features", including the 32-bit ones.
+var uninstallKeys = []string{
+	`SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`,
+	`SOFTWARE\WOW6432Node\Microsoft\Windows\CurrentVersion\Uninstall`,
+}
+
+// programFilesJavaDirectories are the installation directories of the vendors below Program Files.
+var programFilesJavaDirectories = []string{
+	`Java\*`, `Eclipse Adoptium\*`, `Eclipse Foundation\*`, `AdoptOpenJDK\*`, `Zulu\*`, `Amazon Corretto\*`,
+	`BellSoft\*`, `Microsoft\jdk-*`, `Semeru\*`, `SapMachine\*\*`, `RedHat\*`,
+}
+
+var javaProductName = regexp.MustCompile(`(?i)\b(java|jdk|jre|openjdk|temurin|zulu|corretto|liberica|semeru|sapmachine|graalvm)\b`)
+var nonJavaProductName = regexp.MustCompile(`(?i)auto updater|javascript|access bridge|\bsdk for\b`)
+var productCode = regexp.MustCompile(`^\{[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}\}$`)
+
+// uninstallEntry holds the values of a key below Uninstall.
+type uninstallEntry struct {
+	Key             string
+	DisplayName     string
+	DisplayVersion  string
+	Publisher       string
+	InstallLocation string
+	InstallDate     string
+}
+
+// windowsInstallation is a java installation registered by its installer or found in its default directory.
+type windowsInstallation struct {
+	Home        string
+	Source      string
+	ProductCode string
+	ProductName string
+	Publisher   string
+	InstallDate string
+}
+
+func detectWindowsInstallationsMain() []JavaInfo {
+	log.Infof("Starting detection '%s'...", WindowsInstallations)
+	var result []JavaInfo
+	scanTimestamp := time.Now()
+	hostname, _ := os.Hostname()
+
+	installations := windowsInstallationsOf(readUninstallEntries(), findProgramFilesJavaHomes(programFilesDirectories()))
+	analyzed := map[string]JavaInfo{}
+	for _, installation := range installations {
+		info := JavaInfo{}
+		if installation.Home != "" {
+			info = analyzeJavaBinaryOnce(analyzed, windowsJavaBinary(installation.Home))
+		} else {
+			info.ErrorText = "installer did not register an install location"
+		}
+		info.DetectionMethod = WindowsInstallations
+		info.ScanTimestamp = scanTimestamp
+		info.Hostname = hostname
+		info.ConfigSource = installation.Source
+		info.ProductCode = installation.ProductCode
+		info.ProductName = installation.ProductName
+		info.Publisher = installation.Publisher
+		info.InstallDate = installation.InstallDate
+		log.Infof("Found java installation %s in %s (%s)", installation.ProductName, installation.Home, installation.Source)
+		result = append(result, info)
+	}
+	log.Infof("number of installed java products: %d!", len(result))
+	return result
+}
+
+// windowsInstallationsOf returns the java products of the uninstall entries followed by the java homes
+// below Program Files, that no installer registered. Installers, that do not register their install location,
+// get the home of their vendor directory and major version assigned, that no other installer registered.
+func windowsInstallationsOf(entries []uninstallEntry, homes []string) []windowsInstallation {
+	var installations []windowsInstallation
+	registered := map[string]bool{}
+	homeKey := func(home string) string { return strings.ToLower(strings.TrimRight(home, `\/`)) }
+	for _, entry := range entries {
+		if !isJavaUninstallEntry(entry) {
+			continue
+		}
+		installation := windowsInstallation{
+			Home:        strings.TrimRight(strings.TrimSpace(entry.InstallLocation), `\/`),
+			Source:      `HKLM\` + entry.Key,
+			ProductName: strings.TrimSpace(entry.DisplayName + " " + entry.DisplayVersion),
+			Publisher:   entry.Publisher,
+			InstallDate: formatInstallDate(entry.InstallDate),
+		}
+		if code := entry.Key[strings.LastIndex(entry.Key, `\`)+1:]; productCode.MatchString(code) {
+			installation.ProductCode = code
+		}
+		if installation.Home != "" {
+			registered[homeKey(installation.Home)] = true
+		}
+		installations = append(installations, installation)
+	}
+	for i := range installations {
+		installation := &installations[i]
+		if installation.Home != "" {
+			continue
+		}
+		for _, home := range homes {
+			if !registered[homeKey(home)] && isHomeOfInstallation(home, *installation) {
+				installation.Home = home
+				registered[homeKey(home)] = true
+				break
+			}
+		}
+	}
+	for _, home := range homes {
+		if !registered[homeKey(home)] {
+			installations = append(installations, windowsInstallation{Home: home, Source: home})
+		}
+	}
+	return installations
+}
+
+var versionInName = regexp.MustCompile(`\d+(?:\.\d+)*`)
+
+// isHomeOfInstallation checks whether the java home below Program Files belongs to the installation, that did not
+// register its location: the vendor directory has to be named like the publisher or product and the major versions
+// of the directory and the product have to be the same, e.g. "Amazon Corretto\jdk11.0.21_9" for "Amazon Corretto 11.0.21.9.1".
+func isHomeOfInstallation(home string, installation windowsInstallation) bool {
+	vendorDirectory := normalizedName(programFilesVendorDirectory(home))
+	if vendorDirectory == "" || !strings.Contains(normalizedName(installation.Publisher+" "+installation.ProductName), vendorDirectory) {
+		return false
+	}
+	homeMajor, _ := extractMajorAndBuildNumber(versionInName.FindString(filepath.Base(strings.ReplaceAll(home, `\`, "/"))))
+	productMajor, _ := extractMajorAndBuildNumber(versionInName.FindString(installation.ProductName))
+	return homeMajor != 0 && homeMajor == productMajor
+}
+
+// programFilesVendorDirectory returns the vendor directory below Program Files of a java home found via
+// programFilesJavaDirectories, e.g. "Amazon Corretto" for "C:\Program Files\Amazon Corretto\jdk11.0.21_9".
+func programFilesVendorDirectory(home string) string {
+	components := strings.FieldsFunc(home, func(r rune) bool { return r == '\\' || r == '/' })
+	for _, pattern := range programFilesJavaDirectories {
+		patternComponents := strings.Split(pattern, `\`)
+		if len(components) < len(patternComponents) {
+			continue
+		}
+		homeComponents := components[len(components)-len(patternComponents):]
+		isMatch := true
+		for i, patternComponent := range patternComponents {
+			if matched, _ := filepath.Match(strings.ToLower(patternComponent), strings.ToLower(homeComponents[i])); !matched {
+				isMatch = false
+				break
+			}
+		}
+		if isMatch {
+			return patternComponents[0]
+		}
+	}
+	return ""
+}
+
+// normalizedName lower cases the name and removes its spaces, so that "RedHat" matches "Red Hat, Inc.".
+func normalizedName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", ""))
+}
+
+func isJavaUninstallEntry(entry uninstallEntry) bool {
+	return javaProductName.MatchString(entry.DisplayName) && !nonJavaProductName.MatchString(entry.DisplayName)
+}
+
+// formatInstallDate formats the yyyymmdd install date of the Windows Installer as yyyy-mm-dd,
+// other formats used by installers are returned as they are.
+func formatInstallDate(installDate string) string {
+	if date, err := time.Parse("20060102", strings.TrimSpace(installDate)); err == nil {
+		return date.Format("2006-01-02")
+	}
+	return strings.TrimSpace(installDate)
+}
+
+// findProgramFilesJavaHomes returns the directories below the Program Files directories, that contain a java binary.
+func findProgramFilesJavaHomes(programFiles []string) []string {
+	var homes []string
+	for _, root := range programFiles {
+		for _, pattern := range programFilesJavaDirectories {
+			matches, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(strings.ReplaceAll(pattern, `\`, "/"))))
+			for _, home := range matches {
+				if info, err := os.Stat(filepath.Join(home, "bin", javaBinaryName())); err == nil && !info.IsDir() {
+					homes = append(homes, home)
+				}
+			}
+		}
+	}
+	return uniqueStrings(homes)
+}
+
+func programFilesDirectories() []string {
+	var directories []string
+	for _, variable := range []string{"ProgramW6432", "ProgramFiles", "ProgramFiles(x86)"} {
+		if directory := os.Getenv(variable); directory != "" {
+			directories = append(directories, directory)
+		}
+	}
+	return uniqueStrings(directories)
+}
+
+func windowsJavaBinary(home string) string {
+	return home + `\bin\java.exe`
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_windowsInstallationsOf(t *testing.T) {
+	uninstall := `SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall\`
+	entries := []uninstallEntry{
+		{Key: uninstall + "{26A24AE4-039D-4CA4-87B4-2F64180391F0}", DisplayName: "Java 8 Update 391 (64-bit)", DisplayVersion: "8.0.3910.13",
+			Publisher: "Oracle Corporation", InstallLocation: `C:\Program Files\Java\jre-1.8\`, InstallDate: "20231024"},
+		{Key: uninstall + "{4DD1BEE6-5B8A-4A5A-9B0C-1F3E2C1A2B3C}", DisplayName: "Eclipse Temurin JDK with Hotspot 17.0.9+9 (x64)", DisplayVersion: "17.0.9.9",
+			Publisher: "Eclipse Adoptium", InstallLocation: `C:\Program Files\Eclipse Adoptium\jdk-17.0.9.9-hotspot\`, InstallDate: "20231101"},
+		{Key: uninstall + "Amazon Corretto 11", DisplayName: "Amazon Corretto 11.0.21.9.1 (x64)", Publisher: "Amazon"},
+		{Key: uninstall + "{0B5E8A0C-6C2E-4C3B-9C8E-3A1B2C3D4E5F}", DisplayName: "Red Hat Build of OpenJDK 8 (x64)", Publisher: "Red Hat, Inc."},
+		{Key: uninstall + "Zulu 8", DisplayName: "Zulu JDK 8.74.0.17 (8.0.392), 64-bit", Publisher: "Azul Systems, Inc."},
+		{Key: uninstall + "{4A03706F-666A-4037-7777-5F2748764D10}", DisplayName: "Java Auto Updater", Publisher: "Oracle Corporation"},
+		{Key: uninstall + "Notepad++", DisplayName: "Notepad++ (64-bit x64)", InstallLocation: `C:\Program Files\Notepad++`},
+	}
+	homes := []string{`C:\Program Files\Eclipse Adoptium\jdk-17.0.9.9-hotspot`, `C:\Program Files\Microsoft\jdk-21.0.1.12-hotspot`,
+		`C:\Program Files\Amazon Corretto\jdk17.0.9_8`, `C:\Program Files\Amazon Corretto\jdk11.0.21_9`,
+		`C:\Program Files\RedHat\java-1.8.0-openjdk-1.8.0.392-1`, `C:\Program Files\Zulu\zulu-17`}
+
+	want := []windowsInstallation{
+		{Home: `C:\Program Files\Java\jre-1.8`, Source: `HKLM\` + uninstall + "{26A24AE4-039D-4CA4-87B4-2F64180391F0}",
+			ProductCode: "{26A24AE4-039D-4CA4-87B4-2F64180391F0}", ProductName: "Java 8 Update 391 (64-bit) 8.0.3910.13",
+			Publisher: "Oracle Corporation", InstallDate: "2023-10-24"},
+		{Home: `C:\Program Files\Eclipse Adoptium\jdk-17.0.9.9-hotspot`, Source: `HKLM\` + uninstall + "{4DD1BEE6-5B8A-4A5A-9B0C-1F3E2C1A2B3C}",
+			ProductCode: "{4DD1BEE6-5B8A-4A5A-9B0C-1F3E2C1A2B3C}", ProductName: "Eclipse Temurin JDK with Hotspot 17.0.9+9 (x64) 17.0.9.9",
+			Publisher: "Eclipse Adoptium", InstallDate: "2023-11-01"},
+		// installers without install location get the home of their vendor directory and major version
+		{Home: `C:\Program Files\Amazon Corretto\jdk11.0.21_9`, Source: `HKLM\` + uninstall + "Amazon Corretto 11",
+			ProductName: "Amazon Corretto 11.0.21.9.1 (x64)", Publisher: "Amazon"},
+		{Home: `C:\Program Files\RedHat\java-1.8.0-openjdk-1.8.0.392-1`, Source: `HKLM\` + uninstall + "{0B5E8A0C-6C2E-4C3B-9C8E-3A1B2C3D4E5F}",
+			ProductCode: "{0B5E8A0C-6C2E-4C3B-9C8E-3A1B2C3D4E5F}", ProductName: "Red Hat Build of OpenJDK 8 (x64)", Publisher: "Red Hat, Inc."},
+		{Source: `HKLM\` + uninstall + "Zulu 8", ProductName: "Zulu JDK 8.74.0.17 (8.0.392), 64-bit", Publisher: "Azul Systems, Inc."},
+		{Home: `C:\Program Files\Microsoft\jdk-21.0.1.12-hotspot`, Source: `C:\Program Files\Microsoft\jdk-21.0.1.12-hotspot`},
+		{Home: `C:\Program Files\Amazon Corretto\jdk17.0.9_8`, Source: `C:\Program Files\Amazon Corretto\jdk17.0.9_8`},
+		{Home: `C:\Program Files\Zulu\zulu-17`, Source: `C:\Program Files\Zulu\zulu-17`},
+	}
+	if got := windowsInstallationsOf(entries, homes); !reflect.DeepEqual(got, want) {
+		t.Errorf("windowsInstallationsOf() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_findProgramFilesJavaHomes(t *testing.T) {
+	programFiles := t.TempDir()
+	for _, home := range []string{"Zulu/zulu-17", "Microsoft/jdk-21.0.1.12-hotspot", "Microsoft/Edge", "Java/empty"} {
+		if err := os.MkdirAll(filepath.Join(programFiles, home, "bin"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, home := range []string{"Zulu/zulu-17", "Microsoft/jdk-21.0.1.12-hotspot"} {
+		if err := os.WriteFile(filepath.Join(programFiles, home, "bin", javaBinaryName()), nil, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{filepath.Join(programFiles, "Zulu", "zulu-17"), filepath.Join(programFiles, "Microsoft", "jdk-21.0.1.12-hotspot")}
+	if got := findProgramFilesJavaHomes([]string{programFiles}); !reflect.DeepEqual(got, want) {
+		t.Errorf("findProgramFilesJavaHomes() = %v, want %v", got, want)
+	}
+}
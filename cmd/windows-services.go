@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// procrunKeys are the registry keys of the services installed by procrun, e.g. Tomcat9.exe, including the 32-bit ones.
+var procrunKeys = []string{`SOFTWARE\Apache Software Foundation\Procrun 2.0`, `SOFTWARE\WOW6432Node\Apache Software Foundation\Procrun 2.0`}
+
+var windowsVariable = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_()]*)%`)
+
+// windowsServiceConfig is the configuration of a windows service read from the service control manager and the
+// registry parameters of the service wrappers.
+type windowsServiceConfig struct {
+	Name      string
+	ImagePath string
+	StartType uint32
+	State     uint32
+	Account   string
+	// Jvm and JavaHome of procrun's Parameters\Java key
+	ProcrunKey      string
+	ProcrunJvm      string
+	ProcrunJavaHome string
+	// RuntimeLib of the java installation registered below JavaSoft, which procrun uses for Jvm=auto
+	JavaSoftRuntimeLib string
+	// Application of the Parameters key of NSSM
+	NssmApplication string
+}
+
+// findJavaWindowsServices returns the windows services launching a JVM, either java.exe or jvm.dll loaded by a wrapper.
+func findJavaWindowsServices() []javaService {
+	return windowsJavaServicesOf(readWindowsServiceConfigs())
+}
+
+// windowsJavaServicesOf returns the services of the configurations, that launch a JVM.
+// Services, whose JVM cannot be resolved, are returned with an error text.
+func windowsJavaServicesOf(configs []windowsServiceConfig) []javaService {
+	var services []javaService
+	for _, config := range configs {
+		javaBinary, source := resolveWindowsServiceJava(config)
+		if source == "" {
+			continue
+		}
+		username := config.Account
+		if username == "" {
+			username = "LocalSystem"
+		}
+		service := javaService{Name: config.Name, State: windowsServiceState(config.StartType, config.State),
+			Username: username, Source: source, JavaBinary: javaBinary}
+		// the service launches a JVM, that cannot be resolved
+		if javaBinary == "" {
+			service.ErrorText = "cannot resolve the JVM configured in " + source
+		}
+		services = append(services, service)
+	}
+	return services
+}
+
+// resolveWindowsServiceJava returns the java binary of the JVM a service runs and where it is configured,
+// an empty source if the service does not launch a JVM and an empty java binary if the JVM cannot be resolved.
+func resolveWindowsServiceJava(config windowsServiceConfig) (string, string) {
+	serviceKey := `HKLM\SYSTEM\CurrentControlSet\Services\` + config.Name
+	words := splitWindowsCommandLine(config.ImagePath)
+	if len(words) == 0 {
+		return "", ""
+	}
+	if isJavaBinaryName(windowsBase(words[0])) {
+		return words[0], serviceKey
+	}
+	if config.ProcrunJvm != "" || config.ProcrunJavaHome != "" || containsProcrunCommand(words[1:]) {
+		return resolveProcrunJvm(config.ProcrunJvm, config.ProcrunJavaHome, config.JavaSoftRuntimeLib), `HKLM\` + config.ProcrunKey
+	}
+	if application := strings.Trim(config.NssmApplication, `"`); isJavaBinaryName(windowsBase(application)) {
+		return application, serviceKey + `\Parameters`
+	}
+	if strings.Contains(strings.ToLower(windowsBase(words[0])), "wrapper") {
+		for _, word := range words[1:] {
+			if strings.HasSuffix(strings.ToLower(word), ".conf") {
+				// the wrapper resolves a relative configuration file against its own directory
+				if !filepath.IsAbs(word) {
+					word = filepath.Join(filepath.Dir(words[0]), word)
+				}
+				return resolveWrapperJavaCommand(word), word
+			}
+		}
+	}
+	return "", ""
+}
+
+// splitWindowsCommandLine splits an image path into its words, double quotes group words containing spaces.
+func splitWindowsCommandLine(commandLine string) []string {
+	var words []string
+	var word strings.Builder
+	quoted, inWord := false, false
+	for _, c := range commandLine {
+		switch {
+		case c == '"':
+			quoted = !quoted
+			inWord = true
+		case (c == ' ' || c == '\t') && !quoted:
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// containsProcrunCommand checks for the //RS// command procrun runs services with.
+func containsProcrunCommand(arguments []string) bool {
+	for _, argument := range arguments {
+		if strings.HasPrefix(strings.ToUpper(argument), "//RS") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveProcrunJvm returns the java binary of procrun's Jvm parameter, the path of jvm.dll or "auto".
+// For "auto", procrun uses the JavaHome parameter, if given, and the RuntimeLib registered by the java installer
+// below JavaSoft otherwise.
+func resolveProcrunJvm(jvm string, javaHome string, javaSoftRuntimeLib string) string {
+	jvm = strings.TrimSpace(jvm)
+	if jvm == "" || strings.EqualFold(jvm, "auto") {
+		if javaHome = strings.TrimRight(strings.TrimSpace(javaHome), `\/`); javaHome != "" {
+			return javaHome + `\bin\java.exe`
+		}
+		if jvm = strings.TrimSpace(javaSoftRuntimeLib); jvm == "" {
+			return ""
+		}
+	}
+	// e.g. C:\jdk-17\bin\server\jvm.dll or C:\jdk1.8.0_391\jre\bin\server\jvm.dll
+	return windowsDir(windowsDir(jvm)) + `\java.exe`
+}
+
+// resolveWrapperJavaCommand returns the java binary of the wrapper.java.command of a Java Service Wrapper configuration.
+func resolveWrapperJavaCommand(wrapperConf string) string {
+	content, err := os.ReadFile(wrapperConf)
+	if err != nil {
+		return ""
+	}
+	// the wrapper takes backslashes literally, unlike java properties files
+	properties := parseReleaseFile(string(content))
+	javaCommand := windowsVariable.ReplaceAllStringFunc(properties["wrapper.java.command"], func(variable string) string {
+		name := strings.Trim(variable, "%")
+		if value := properties["set."+name]; value != "" {
+			return value
+		}
+		return os.Getenv(name)
+	})
+	if javaCommand == "" || strings.Contains(javaCommand, "%") || !strings.ContainsAny(javaCommand, `\/`) {
+		return ""
+	}
+	if !strings.HasSuffix(strings.ToLower(javaCommand), ".exe") {
+		javaCommand += ".exe"
+	}
+	return javaCommand
+}
+
+// windowsServiceState formats the start type and current state of a service like the state of a systemd unit,
+// e.g. "auto/running".
+func windowsServiceState(startType uint32, state uint32) string {
+	startTypes := []string{"boot", "system", "auto", "manual", "disabled"}
+	states := []string{"unknown", "stopped", "start-pending", "stop-pending", "running", "continue-pending", "pause-pending", "paused"}
+	formatted := "unknown"
+	if int(startType) < len(startTypes) {
+		formatted = startTypes[startType]
+	}
+	if int(state) < len(states) {
+		return formatted + "/" + states[state]
+	}
+	return formatted + "/unknown"
+}
+
+// windowsBase returns the last element of a windows path on any platform.
+func windowsBase(path string) string {
+	return path[strings.LastIndexAny(path, `\/`)+1:]
+}
+
+// windowsDir returns all but the last element of a windows path on any platform.
+func windowsDir(path string) string {
+	if index := strings.LastIndexAny(path, `\/`); index >= 0 {
+		return path[:index]
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_resolveWindowsServiceJava(t *testing.T) {
+	wrapperConf := filepath.Join(t.TempDir(), "wrapper.conf")
+	if err := os.WriteFile(wrapperConf, []byte("set.JAVA_HOME=C:\\jdk-11\nwrapper.java.command=%JAVA_HOME%\\bin\\java\nwrapper.java.mainclass=org.tanukisoftware.wrapper.WrapperSimpleApp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the wrapper resolves a relative configuration file against its own directory
+	wrapperHome := t.TempDir()
+	writeFixtureFiles(t, wrapperHome, map[string]string{"conf/wrapper.conf": "wrapper.java.command=C:\\jdk-17\\bin\\java.exe\n"})
+	procrunKey := `SOFTWARE\Apache Software Foundation\Procrun 2.0\Tomcat9\Parameters\Java`
+	tests := []struct {
+		name       string
+		config     windowsServiceConfig
+		wantBinary string
+		wantSource string
+	}{
+		{"java.exe", windowsServiceConfig{Name: "app", ImagePath: `"C:\Program Files\Java\jdk-17\bin\java.exe" -jar C:\app\app.jar`},
+			`C:\Program Files\Java\jdk-17\bin\java.exe`, `HKLM\SYSTEM\CurrentControlSet\Services\app`},
+		{"procrun jvm.dll", windowsServiceConfig{Name: "Tomcat9", ImagePath: `"C:\Program Files\Apache Software Foundation\Tomcat 9.0\bin\Tomcat9.exe" //RS//Tomcat9`,
+			ProcrunKey: procrunKey, ProcrunJvm: `C:\Program Files\Eclipse Adoptium\jdk-17.0.9.9-hotspot\bin\server\jvm.dll`},
+			`C:\Program Files\Eclipse Adoptium\jdk-17.0.9.9-hotspot\bin\java.exe`, `HKLM\` + procrunKey},
+		{"procrun jre of jdk 8", windowsServiceConfig{Name: "Tomcat9", ImagePath: `C:\tomcat\bin\Tomcat9.exe //RS//Tomcat9`,
+			ProcrunKey: procrunKey, ProcrunJvm: `C:\Program Files\Java\jdk1.8.0_391\jre\bin\server\jvm.dll`},
+			`C:\Program Files\Java\jdk1.8.0_391\jre\bin\java.exe`, `HKLM\` + procrunKey},
+		{"procrun auto with java home", windowsServiceConfig{Name: "Tomcat9", ImagePath: `C:\tomcat\bin\Tomcat9.exe //RS//Tomcat9`,
+			ProcrunKey: procrunKey, ProcrunJvm: "auto", ProcrunJavaHome: `C:\jdk-21\`},
+			`C:\jdk-21\bin\java.exe`, `HKLM\` + procrunKey},
+		{"procrun auto", windowsServiceConfig{Name: "Tomcat9", ImagePath: `C:\tomcat\bin\Tomcat9.exe //RS//Tomcat9`, ProcrunKey: procrunKey, ProcrunJvm: "auto"},
+			"", `HKLM\` + procrunKey},
+		{"procrun auto with registered runtime", windowsServiceConfig{Name: "Tomcat9", ImagePath: `C:\tomcat\bin\Tomcat9.exe //RS//Tomcat9`,
+			ProcrunKey: procrunKey, JavaSoftRuntimeLib: `C:\Program Files\Java\jre1.8.0_391\bin\server\jvm.dll`},
+			`C:\Program Files\Java\jre1.8.0_391\bin\java.exe`, `HKLM\` + procrunKey},
+		{"nssm", windowsServiceConfig{Name: "app", ImagePath: `C:\nssm\win64\nssm.exe`, NssmApplication: `C:\jdk-17\bin\java.exe`},
+			`C:\jdk-17\bin\java.exe`, `HKLM\SYSTEM\CurrentControlSet\Services\app\Parameters`},
+		{"java service wrapper", windowsServiceConfig{Name: "app", ImagePath: `"C:\app\bin\wrapper.exe" -s ` + wrapperConf},
+			`C:\jdk-11\bin\java.exe`, wrapperConf},
+		{"java service wrapper with relative configuration", windowsServiceConfig{Name: "app",
+			ImagePath: `"` + filepath.Join(wrapperHome, "bin", "wrapper.exe") + `" -s ` + filepath.Join("..", "conf", "wrapper.conf")},
+			`C:\jdk-17\bin\java.exe`, filepath.Join(wrapperHome, "conf", "wrapper.conf")},
+		{"no jvm", windowsServiceConfig{Name: "Spooler", ImagePath: `C:\Windows\System32\spoolsv.exe`}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBinary, gotSource := resolveWindowsServiceJava(tt.config)
+			if gotBinary != tt.wantBinary || gotSource != tt.wantSource {
+				t.Errorf("resolveWindowsServiceJava() = %v, %v, want %v, %v", gotBinary, gotSource, tt.wantBinary, tt.wantSource)
+			}
+		})
+	}
+}
+
+func Test_windowsJavaServicesOf(t *testing.T) {
+	procrunKey := `SOFTWARE\Apache Software Foundation\Procrun 2.0\Tomcat9\Parameters\Java`
+	configs := []windowsServiceConfig{
+		{Name: "app", ImagePath: `C:\jdk-17\bin\java.exe -jar C:\app\app.jar`, StartType: 2, State: 4, Account: `.\app`},
+		{Name: "Tomcat9", ImagePath: `C:\tomcat\bin\Tomcat9.exe //RS//Tomcat9`, StartType: 3, State: 1, ProcrunKey: procrunKey, ProcrunJvm: "auto"},
+		{Name: "Spooler", ImagePath: `C:\Windows\System32\spoolsv.exe`, StartType: 2, State: 4},
+	}
+	want := []javaService{
+		{Name: "app", State: "auto/running", Username: `.\app`, Source: `HKLM\SYSTEM\CurrentControlSet\Services\app`, JavaBinary: `C:\jdk-17\bin\java.exe`},
+		// no java installation is registered for Jvm=auto
+		{Name: "Tomcat9", State: "manual/stopped", Username: "LocalSystem", Source: `HKLM\` + procrunKey,
+			ErrorText: `cannot resolve the JVM configured in HKLM\` + procrunKey},
+	}
+	if got := windowsJavaServicesOf(configs); !reflect.DeepEqual(got, want) {
+		t.Errorf("windowsJavaServicesOf() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_splitWindowsCommandLine(t *testing.T) {
+	got := splitWindowsCommandLine(`"C:\Program Files\Tomcat\bin\Tomcat9.exe"  //RS//Tomcat9 --Jvm="C:\Program Files\jdk\bin\server\jvm.dll"`)
+	want := []string{`C:\Program Files\Tomcat\bin\Tomcat9.exe`, "//RS//Tomcat9", `--Jvm=C:\Program Files\jdk\bin\server\jvm.dll`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitWindowsCommandLine() = %q, want %q", got, want)
+	}
+}
+
+func Test_windowsServiceState(t *testing.T) {
+	if got := windowsServiceState(2, 4); got != "auto/running" {
+		t.Errorf("windowsServiceState() = %v, want auto/running", got)
+	}
+	if got := windowsServiceState(4, 1); got != "disabled/stopped" {
+		t.Errorf("windowsServiceState() = %v, want disabled/stopped", got)
+	}
+}